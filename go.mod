@@ -1,3 +1,5 @@
 module github.com/arr4n/shed
 
 go 1.24.8
+
+require github.com/google/go-cmp v0.7.0
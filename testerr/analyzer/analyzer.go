@@ -0,0 +1,276 @@
+// Package analyzer provides an [analysis.Analyzer] that reports brittle error
+// assertions in test files and suggests the equivalent testerr matchers.
+//
+// The following patterns are reported:
+//
+//   - Comparison of an error's message with a constant string, e.g.
+//     `err.Error() != "…"`, suggesting [testerr.MessageIs];
+//   - [strings.Contains], [strings.HasPrefix], or [strings.HasSuffix] applied
+//     to an error's message and a constant string, suggesting
+//     [testerr.Contains], [testerr.HasPrefix], or [testerr.HasSuffix];
+//   - Comparison of two non-nil errors with == or !=, e.g. `err == ErrFoo`,
+//     suggesting [testerr.Is]; and
+//   - Use of [testerr.Equals], suggesting [testerr.Is].
+//
+// Suggested fixes are only provided in files that already import the testerr
+// package. Comparisons are replaced with equivalent expressions involving
+// [testerr.Diff], e.g. `testerr.Diff(err, testerr.MessageIs("…")) != ""`,
+// which can then be refactored to report the diff itself.
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const testerrPath = "github.com/arr4n/shed/testerr"
+
+// Analyzer reports brittle error assertions in test files.
+var Analyzer = &analysis.Analyzer{
+	Name:     "testerr",
+	Doc:      "report brittle error assertions in tests and suggest testerr matchers",
+	URL:      "https://pkg.go.dev/github.com/arr4n/shed/testerr/analyzer",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodes := []ast.Node{(*ast.File)(nil), (*ast.BinaryExpr)(nil), (*ast.CallExpr)(nil)}
+
+	insp.WithStack(nodes, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		if f, ok := n.(*ast.File); ok {
+			return strings.HasSuffix(pass.Fset.File(f.Pos()).Name(), "_test.go")
+		}
+
+		r := reporter{
+			pass:   pass,
+			file:   stack[0].(*ast.File),
+			parent: stack[len(stack)-2],
+		}
+		switch n := n.(type) {
+		case *ast.BinaryExpr:
+			r.checkComparison(n)
+		case *ast.CallExpr:
+			r.checkCall(n)
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// A reporter reports diagnostics for nodes in a specific file.
+type reporter struct {
+	pass   *analysis.Pass
+	file   *ast.File
+	parent ast.Node // of the node being checked
+}
+
+// checkComparison reports == and != comparisons of error messages with
+// constant strings, and of errors with each other.
+func (r reporter) checkComparison(cmp *ast.BinaryExpr) {
+	pass := r.pass
+	if cmp.Op != token.EQL && cmp.Op != token.NEQ {
+		return
+	}
+	diffOp := "=="
+	if cmp.Op == token.NEQ {
+		diffOp = "!="
+	}
+
+	for _, sides := range [][2]ast.Expr{{cmp.X, cmp.Y}, {cmp.Y, cmp.X}} {
+		got, ok := errorMessage(pass, sides[0])
+		if !ok || !isConstString(pass, sides[1]) {
+			continue
+		}
+		r.report(cmp, "MessageIs", got, sides[1], diffOp,
+			"error message compared with %s; use testerr.MessageIs()", types.ExprString(sides[1]),
+		)
+		return
+	}
+
+	if !isError(pass, cmp.X) || !isError(pass, cmp.Y) || isNil(pass, cmp.X) || isNil(pass, cmp.Y) {
+		return
+	}
+	got, want := cmp.X, cmp.Y
+	if isPackageVar(pass, got) && !isPackageVar(pass, want) {
+		got, want = want, got
+	}
+	r.report(cmp, "Is", got, want, diffOp,
+		"errors compared with %s, which doesn't account for wrapping; use testerr.Is()", cmp.Op,
+	)
+}
+
+// stringsFuncs maps functions in the strings package to their testerr
+// equivalents.
+var stringsFuncs = map[string]string{
+	"Contains":  "Contains",
+	"HasPrefix": "HasPrefix",
+	"HasSuffix": "HasSuffix",
+}
+
+// checkCall reports uses of [strings] functions on error messages, and of
+// [testerr.Equals].
+func (r reporter) checkCall(call *ast.CallExpr) {
+	pass := r.pass
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil || fn.Pkg() == nil {
+		return
+	}
+
+	switch path, name := fn.Pkg().Path(), fn.Name(); {
+	case path == "strings" && stringsFuncs[name] != "" && len(call.Args) == 2:
+		got, ok := errorMessage(pass, call.Args[0])
+		if !ok || !isConstString(pass, call.Args[1]) {
+			return
+		}
+		r.report(call, stringsFuncs[name], got, call.Args[1], "==",
+			"strings.%s() applied to error message; use testerr.%s()", name, stringsFuncs[name],
+		)
+
+	case path == testerrPath && name == "Equals" && fn.Signature().Recv() == nil:
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			Message: "testerr.Equals() doesn't account for wrapping; use testerr.Is()",
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message: "Replace with testerr.Is()",
+				TextEdits: []analysis.TextEdit{{
+					Pos:     sel.Sel.Pos(),
+					End:     sel.Sel.End(),
+					NewText: []byte("Is"),
+				}},
+			}},
+		})
+	}
+}
+
+// report reports `node`, suggesting its replacement with a check that the diff
+// of `got` and `testerr.<matcher>(arg)` compares to the empty string with
+// `diffOp`.
+func (r reporter) report(node ast.Node, matcher string, got, arg ast.Expr, diffOp string, format string, args ...any) {
+	d := analysis.Diagnostic{
+		Pos:     node.Pos(),
+		End:     node.End(),
+		Message: fmt.Sprintf(format, args...),
+	}
+	pkg, ok := testerrName(r.file)
+	if !ok {
+		r.pass.Report(d)
+		return
+	}
+
+	// The replacement is a comparison so a negated node is replaced in its
+	// entirety, with the comparison inverted, and operators of higher
+	// precedence require parentheses.
+	replace, parens := node, false
+	switch p := r.parent.(type) {
+	case *ast.UnaryExpr:
+		if p.Op == token.NOT {
+			replace = p
+			diffOp = map[string]string{"==": "!=", "!=": "=="}[diffOp]
+		} else {
+			parens = true
+		}
+	case *ast.BinaryExpr:
+		parens = p.Op.Precedence() >= token.EQL.Precedence()
+	}
+
+	text := fmt.Sprintf(
+		"%[1]s.Diff(%[2]s, %[1]s.%[3]s(%[4]s)) %[5]s \"\"",
+		pkg, types.ExprString(got), matcher, types.ExprString(arg), diffOp,
+	)
+	if parens {
+		text = "(" + text + ")"
+	}
+	d.SuggestedFixes = []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("Replace with testerr.%s()", matcher),
+		TextEdits: []analysis.TextEdit{{
+			Pos:     replace.Pos(),
+			End:     replace.End(),
+			NewText: []byte(text),
+		}},
+	}}
+	r.pass.Report(d)
+}
+
+// testerrName returns the name by which the testerr package is imported in
+// `file`, if at all. Dot and blank imports are treated as not importing it.
+func testerrName(file *ast.File) (string, bool) {
+	for _, imp := range file.Imports {
+		if path, err := strconv.Unquote(imp.Path.Value); err != nil || path != testerrPath {
+			continue
+		}
+		if imp.Name == nil {
+			return "testerr", true
+		}
+		if n := imp.Name.Name; n != "." && n != "_" {
+			return n, true
+		}
+	}
+	return "", false
+}
+
+// errorMessage reports whether `e` is a call to the `Error()` method of an
+// error, and returns the error.
+func errorMessage(pass *analysis.Pass, e ast.Expr) (ast.Expr, bool) {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return nil, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Error" || !isError(pass, sel.X) {
+		return nil, false
+	}
+	return sel.X, true
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// isError reports whether the type of `e` implements the error interface.
+func isError(pass *analysis.Pass, e ast.Expr) bool {
+	t := pass.TypesInfo.TypeOf(e)
+	return t != nil && types.Implements(t, errorType)
+}
+
+func isConstString(pass *analysis.Pass, e ast.Expr) bool {
+	v := pass.TypesInfo.Types[e].Value
+	return v != nil && v.Kind() == constant.String
+}
+
+func isNil(pass *analysis.Pass, e ast.Expr) bool {
+	return pass.TypesInfo.Types[e].IsNil()
+}
+
+// isPackageVar reports whether `e` refers to a package-level variable, such as
+// a sentinel error.
+func isPackageVar(pass *analysis.Pass, e ast.Expr) bool {
+	var id *ast.Ident
+	switch e := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return false
+	}
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	return ok && v.Parent() == v.Pkg().Scope()
+}
@@ -0,0 +1,9 @@
+// The testerrvet command runs the testerr [analyzer.Analyzer].
+package main
+
+import (
+	"github.com/arr4n/shed/testerr/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() { singlechecker.Main(analyzer.Analyzer) }
@@ -0,0 +1,13 @@
+package a
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// Check isn't in a test file so isn't reported.
+func Check(err error) bool {
+	return err == ErrNotFound || err.Error() == "not found" || strings.Contains(err.Error(), "found")
+}
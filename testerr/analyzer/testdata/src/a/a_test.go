@@ -0,0 +1,66 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+const msg = "not found"
+
+func TestMessages(t *testing.T) {
+	err := fmt.Errorf("lookup: %w", ErrNotFound)
+
+	if err.Error() != "lookup: not found" { // want `error message compared with "lookup: not found"; use testerr.MessageIs\(\)`
+		t.Error("wrong message")
+	}
+	if "lookup: not found" == err.Error() { // want `error message compared with "lookup: not found"; use testerr.MessageIs\(\)`
+		t.Log("reversed")
+	}
+	if err != nil && (err.Error() == msg) { // want `error message compared with msg; use testerr.MessageIs\(\)`
+		t.Log("wrapped in a larger expression")
+	}
+	if !strings.Contains(err.Error(), "not found") { // want `strings.Contains\(\) applied to error message; use testerr.Contains\(\)`
+		t.Error("missing substring")
+	}
+	if strings.HasPrefix(err.Error(), "lookup") { // want `strings.HasPrefix\(\) applied to error message; use testerr.HasPrefix\(\)`
+		t.Log("prefix")
+	}
+
+	// Not reported: non-constant strings and other functions.
+	var other string
+	_ = err.Error() == other
+	_ = strings.Index(err.Error(), "x")
+	_ = strings.Contains(other, "x")
+}
+
+func TestComparisons(t *testing.T) {
+	err := fmt.Errorf("read: %w", io.EOF)
+
+	if err == io.EOF { // want `errors compared with ==, which doesn't account for wrapping; use testerr.Is\(\)`
+		t.Log("never true as err is wrapped")
+	}
+	if ErrNotFound != err { // want `errors compared with !=, which doesn't account for wrapping; use testerr.Is\(\)`
+		t.Log("sentinel on the left")
+	}
+
+	t.Run("in closure", func(t *testing.T) {
+		if !(err == ErrNotFound) { // want `errors compared with ==, which doesn't account for wrapping; use testerr.Is\(\)`
+			t.Log("negated")
+		}
+	})
+
+	// Not reported: nil checks and errors.Is().
+	if err != nil || nil == err || errors.Is(err, io.EOF) {
+		t.Log("fine")
+	}
+}
+
+func TestEquals(t *testing.T) {
+	_ = testerr.Diff(io.EOF, testerr.Equals(io.EOF)) // want `testerr.Equals\(\) doesn't account for wrapping; use testerr.Is\(\)`
+	_ = testerr.Diff(io.EOF, testerr.Is(io.EOF))
+}
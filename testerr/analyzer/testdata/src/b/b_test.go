@@ -0,0 +1,17 @@
+package b
+
+import (
+	"io"
+	"testing"
+)
+
+// Without an import of testerr, diagnostics have no suggested fixes.
+func TestNoImport(t *testing.T) {
+	var err error
+	if err == io.EOF { // want `errors compared with ==`
+		t.Log("no fix")
+	}
+	if err.Error() != "EOF" { // want `error message compared with "EOF"`
+		t.Log("no fix")
+	}
+}
@@ -0,0 +1,12 @@
+// Package testerr is a stub of the real package, sufficient for type checking.
+package testerr
+
+type Want interface{ ErrDiff(error) string }
+
+func Diff(error, Want) string { return "" }
+func Is(error) Want           { return nil }
+func Equals(error) Want       { return nil }
+func Contains(string) Want    { return nil }
+func HasPrefix(string) Want   { return nil }
+func HasSuffix(string) Want   { return nil }
+func MessageIs(string) Want   { return nil }
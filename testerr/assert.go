@@ -0,0 +1,115 @@
+package testerr
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// Assert reports a test error, via `t.Errorf()`, if [Diff] of `got` and `want`
+// is non-empty, and returns whether the diff was empty. The reported message is
+// the formatted prefix followed by a space and the diff, mirroring the common
+// pattern of `t.Errorf("Something(arg) %s", diff)`.
+func Assert(t testing.TB, got error, want Want, msgFormat string, args ...any) bool {
+	t.Helper()
+	diff := Diff(got, want)
+	if diff == "" {
+		return true
+	}
+	t.Errorf("%s %s", fmt.Sprintf(msgFormat, args...), diff)
+	return false
+}
+
+// Require is equivalent to [Assert] except that it uses `t.Fatalf()`, stopping
+// the test on mismatch.
+func Require(t testing.TB, got error, want Want, msgFormat string, args ...any) {
+	t.Helper()
+	if diff := Diff(got, want); diff != "" {
+		t.Fatalf("%s %s", fmt.Sprintf(msgFormat, args...), diff)
+	}
+}
+
+// Must returns `v` if `err` is nil, otherwise it reports the error via
+// `t.Fatalf()`, stopping the test, which removes the need for an explicit check
+// of errors returned by test setup. As Go doesn't allow a multi-value call to be
+// combined with other arguments, the values are typically assigned first; e.g.
+// `f, err := os.Open(p)` followed by `f = testerr.Must(t, f, err)`.
+// As with all uses of `t.Fatalf()`, Must MUST only be called from the goroutine
+// running the test, not from goroutines that it starts.
+func Must[T any](t testing.TB, v T, err error) T {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Must[%v]() %s", reflect.TypeFor[T](), DiffMessage(err, "nil"))
+	}
+	return v
+}
+
+// Must2 is equivalent to [Must] for functions returning two values and an
+// error.
+func Must2[A, B any](t testing.TB, a A, b B, err error) (A, B) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Must2[%v, %v]() %s", reflect.TypeFor[A](), reflect.TypeFor[B](), DiffMessage(err, "nil"))
+	}
+	return a, b
+}
+
+// SkipIf skips the test, via `t.Skipf()`, if the `got` error is non-nil and
+// matches `want`, which allows integration tests to skip on known
+// environmental failures (e.g. blocked DNS) rather than inspecting messages by
+// hand. The skip message includes `reason`, the matched error, and the [Want]'s
+// description. SkipIf returns true if it skipped, which is only observable with
+// a [testing.TB] whose Skipf() returns; otherwise it does nothing and returns
+// false, so the test can continue to its regular assertions.
+func SkipIf(t testing.TB, got error, want Want, reason string) bool {
+	if got == nil || !Matches(got, want) {
+		return false
+	}
+	t.Helper()
+	t.Skipf("%s: error %s matches %s", reason, quoteMessage(got), Describe(want))
+	return true
+}
+
+// SkipIfAny is equivalent to [SkipIf] for multiple known conditions, keyed by
+// their reasons, skipping on the first match in lexical order of the reasons.
+// The skip message, and therefore the test output, reports which condition
+// triggered.
+func SkipIfAny(t testing.TB, got error, reasons map[string]Want) bool {
+	if got == nil {
+		return false
+	}
+	for _, r := range slices.Sorted(maps.Keys(reasons)) {
+		if w := reasons[r]; Matches(got, w) {
+			t.Helper()
+			return SkipIf(t, got, w, r)
+		}
+	}
+	return false
+}
+
+// Get returns the first error in the `got` error's tree that is a `T`, as found
+// by [errors.As], for further inspection; e.g. of its fields with [cmp.Diff].
+// If there is none, Get reports the diff returned by [TryGet] via
+// `t.Fatalf()`, stopping the test.
+func Get[T error](t testing.TB, got error) T {
+	t.Helper()
+	v, diff := TryGet[T](got)
+	if diff != "" {
+		t.Fatalf("Get[%v]() %s", reflect.TypeFor[T](), diff)
+	}
+	return v
+}
+
+// TryGet is equivalent to [Get] except that, if there is no `T` in the tree,
+// it returns the zero value and a diff, in the same form as that of [IsType],
+// instead of stopping the test.
+func TryGet[T error](got error) (T, string) {
+	var v T
+	if errors.As(got, &v) {
+		return v, ""
+	}
+	return v, typeDiff(got, fmt.Sprintf("error tree containing type %v", reflect.TypeFor[T]()))
+}
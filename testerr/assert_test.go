@@ -0,0 +1,380 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// fakeTB records calls that would otherwise be reported to a real
+// [testing.TB]. Its embedded interface is nil so any other method panics.
+type fakeTB struct {
+	testing.TB
+	helper                bool
+	errors, fatals, skips []string
+}
+
+func (f *fakeTB) Helper() { f.helper = true }
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+// Fatalf mirrors the semantics of [testing.T.Fatalf], which stops the calling
+// goroutine.
+func (f *fakeTB) Fatalf(format string, args ...any) {
+	f.fatals = append(f.fatals, fmt.Sprintf(format, args...))
+	runtime.Goexit()
+}
+
+// Skipf mirrors the semantics of [testing.T.Skipf], which stops the calling
+// goroutine.
+func (f *fakeTB) Skipf(format string, args ...any) {
+	f.skips = append(f.skips, fmt.Sprintf(format, args...))
+	runtime.Goexit()
+}
+
+// run calls `fn` in a new goroutine, as [testing.T.Run] does, and reports
+// whether it returned normally, which it doesn't if stopped by [fakeTB.Fatalf].
+func (f *fakeTB) run(fn func(testing.TB)) (returned bool) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(f)
+		returned = true
+	}()
+	<-done
+	return returned
+}
+
+func TestAssert(t *testing.T) {
+	tests := []struct {
+		name       string
+		got        error
+		want       testerr.Want
+		wantOK     bool
+		wantErrors []string
+	}{
+		{
+			name:   "match",
+			got:    fmt.Errorf("read: %w", io.EOF),
+			want:   testerr.Is(io.EOF),
+			wantOK: true,
+		},
+		{
+			name:       "mismatch",
+			got:        errors.New("uh oh"),
+			want:       testerr.Is(io.EOF),
+			wantErrors: []string{"Read(42) got error uh oh; want error that Is() EOF"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := new(fakeTB)
+			var ok bool
+			fake.run(func(tb testing.TB) {
+				ok = testerr.Assert(tb, tt.got, tt.want, "Read(%d)", 42)
+			})
+
+			if ok != tt.wantOK {
+				t.Errorf("Assert() got %t; want %t", ok, tt.wantOK)
+			}
+			if !fake.helper {
+				t.Error("Assert() didn't call t.Helper()")
+			}
+			if fmt.Sprint(fake.errors) != fmt.Sprint(tt.wantErrors) || len(fake.fatals) != 0 {
+				t.Errorf("Assert() reported errors %q and fatals %q; want errors %q and no fatals", fake.errors, fake.fatals, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	tests := []struct {
+		name         string
+		got          error
+		want         testerr.Want
+		wantReturned bool
+		wantFatals   []string
+	}{
+		{
+			name:         "match",
+			got:          nil,
+			want:         nil,
+			wantReturned: true,
+		},
+		{
+			name:       "mismatch",
+			got:        io.EOF,
+			want:       nil,
+			wantFatals: []string{"Close() got error EOF; want nil"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := new(fakeTB)
+			returned := fake.run(func(tb testing.TB) {
+				testerr.Require(tb, tt.got, tt.want, "Close()")
+			})
+
+			if returned != tt.wantReturned {
+				t.Errorf("Require() returned = %t; want %t", returned, tt.wantReturned)
+			}
+			if !fake.helper {
+				t.Error("Require() didn't call t.Helper()")
+			}
+			if fmt.Sprint(fake.fatals) != fmt.Sprint(tt.wantFatals) || len(fake.errors) != 0 {
+				t.Errorf("Require() reported fatals %q and errors %q; want fatals %q and no errors", fake.fatals, fake.errors, tt.wantFatals)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "x", Err: fs.ErrNotExist}
+	wrapped := fmt.Errorf("load: %w", pathErr)
+
+	t.Run("pointer type", func(t *testing.T) {
+		fake := new(fakeTB)
+		var got *fs.PathError
+		if !fake.run(func(tb testing.TB) { got = testerr.Get[*fs.PathError](tb, wrapped) }) {
+			t.Fatalf("Get[*fs.PathError]() stopped the test with %q", fake.fatals)
+		}
+		if got != pathErr {
+			t.Errorf("Get[*fs.PathError]() got %v; want %v", got, pathErr)
+		}
+		if !fake.helper {
+			t.Error("Get() didn't call t.Helper()")
+		}
+	})
+
+	t.Run("interface type", func(t *testing.T) {
+		fake := new(fakeTB)
+		err := fmt.Errorf("dial: %w", &testerr.FakeNetError{IsTimeout: true})
+		type timeout interface {
+			error
+			Timeout() bool
+		}
+		var got timeout
+		if !fake.run(func(tb testing.TB) { got = testerr.Get[timeout](tb, err) }) {
+			t.Fatalf("Get[<interface>]() stopped the test with %q", fake.fatals)
+		}
+		if !got.Timeout() {
+			t.Errorf("Get[<interface>]().Timeout() got false; want true")
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		fake := new(fakeTB)
+		if fake.run(func(tb testing.TB) { testerr.Get[*fs.PathError](tb, fmt.Errorf("x: %w", io.EOF)) }) {
+			t.Error("Get[*fs.PathError]() of tree without *fs.PathError returned; want test stopped")
+		}
+		want := []string{"Get[*fs.PathError]() got error x: EOF; want error tree containing type *fs.PathError; found types [*fmt.wrapError, *errors.errorString]"}
+		if fmt.Sprint(fake.fatals) != fmt.Sprint(want) {
+			t.Errorf("Get[*fs.PathError]() reported fatals %q; want %q", fake.fatals, want)
+		}
+	})
+}
+
+func TestTryGet(t *testing.T) {
+	got, diff := testerr.TryGet[*fs.PathError](errors.Join(io.EOF, &fs.PathError{Op: "stat"}))
+	if diff != "" || got.Op != "stat" {
+		t.Errorf("TryGet[*fs.PathError](<joined>) got (%v, %q); want Op stat and empty diff", got, diff)
+	}
+
+	got, diff = testerr.TryGet[*fs.PathError](nil)
+	if want := "got error <nil>; want error tree containing type *fs.PathError"; got != nil || diff != want {
+		t.Errorf("TryGet[*fs.PathError](nil) got (%v, %q); want (nil, %q)", got, diff, want)
+	}
+}
+
+func TestMust(t *testing.T) {
+	t.Run("nil error", func(t *testing.T) {
+		fake := new(fakeTB)
+		var got int
+		if !fake.run(func(tb testing.TB) {
+			n, err := strconv.Atoi("42")
+			got = testerr.Must(tb, n, err)
+		}) {
+			t.Fatalf("Must(Atoi(42)) stopped the test with %q", fake.fatals)
+		}
+		if got != 42 {
+			t.Errorf("Must(Atoi(42)) got %d; want 42", got)
+		}
+		if !fake.helper {
+			t.Error("Must() didn't call t.Helper()")
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		fake := new(fakeTB)
+		got := -1
+		returned := fake.run(func(tb testing.TB) {
+			n, err := strconv.Atoi("x")
+			got = testerr.Must(tb, n, err)
+		})
+		if returned || got != -1 {
+			t.Errorf("Must(Atoi(x)) returned = %t with %d; want test stopped before assignment", returned, got)
+		}
+		want := []string{`Must[int]() got error strconv.Atoi: parsing "x": invalid syntax; want nil`}
+		if fmt.Sprint(fake.fatals) != fmt.Sprint(want) {
+			t.Errorf("Must(Atoi(x)) reported fatals %q; want %q", fake.fatals, want)
+		}
+	})
+}
+
+func TestMust2(t *testing.T) {
+	split := func(s string) (string, string, error) {
+		before, after, ok := strings.Cut(s, "=")
+		if !ok {
+			return "", "", fmt.Errorf("%q: %w", s, io.ErrUnexpectedEOF)
+		}
+		return before, after, nil
+	}
+
+	t.Run("nil error", func(t *testing.T) {
+		fake := new(fakeTB)
+		var k, v string
+		if !fake.run(func(tb testing.TB) {
+			k0, v0, err := split("k=v")
+			k, v = testerr.Must2(tb, k0, v0, err)
+		}) {
+			t.Fatalf("Must2(split(k=v)) stopped the test with %q", fake.fatals)
+		}
+		if k != "k" || v != "v" {
+			t.Errorf("Must2(split(k=v)) got (%q, %q); want (k, v)", k, v)
+		}
+		if !fake.helper {
+			t.Error("Must2() didn't call t.Helper()")
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		fake := new(fakeTB)
+		k := "unassigned"
+		returned := fake.run(func(tb testing.TB) {
+			k0, v0, err := split("kv")
+			k, _ = testerr.Must2(tb, k0, v0, err)
+		})
+		if returned || k != "unassigned" {
+			t.Errorf("Must2(split(kv)) returned = %t with %q; want test stopped before assignment", returned, k)
+		}
+		want := []string{`Must2[string, string]() got error "kv": unexpected EOF; want nil`}
+		if fmt.Sprint(fake.fatals) != fmt.Sprint(want) {
+			t.Errorf("Must2(split(kv)) reported fatals %q; want %q", fake.fatals, want)
+		}
+	})
+}
+
+func TestSkipIf(t *testing.T) {
+	errDNS := &net.DNSError{Err: "no such host", Name: "example.com", IsNotFound: true}
+	dnsBlocked := testerr.As(func(e *net.DNSError) string {
+		if !e.IsNotFound {
+			return "not IsNotFound"
+		}
+		return ""
+	})
+
+	tests := []struct {
+		name      string
+		got       error
+		want      testerr.Want
+		wantSkips []string
+	}{
+		{
+			name: "nil error",
+			got:  nil,
+			want: nil,
+		},
+		{
+			name: "mismatch",
+			got:  io.EOF,
+			want: dnsBlocked,
+		},
+		{
+			name:      "match",
+			got:       fmt.Errorf("lookup: %w", errDNS),
+			want:      dnsBlocked,
+			wantSkips: []string{`DNS blocked in sandbox: error "lookup: lookup example.com: no such host" matches ` + testerr.Describe(dnsBlocked)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := new(fakeTB)
+			var skipped bool
+			returned := fake.run(func(tb testing.TB) {
+				skipped = testerr.SkipIf(tb, tt.got, tt.want, "DNS blocked in sandbox")
+			})
+
+			wantSkip := len(tt.wantSkips) > 0
+			if returned == wantSkip || skipped {
+				t.Errorf("SkipIf() returned = %t with %t; want test stopped = %t", returned, skipped, wantSkip)
+			}
+			if fake.helper != wantSkip {
+				t.Errorf("SkipIf() called t.Helper() = %t; want %t", fake.helper, wantSkip)
+			}
+			if fmt.Sprint(fake.skips) != fmt.Sprint(tt.wantSkips) || len(fake.errors)+len(fake.fatals) != 0 {
+				t.Errorf("SkipIf() reported skips %q, errors %q, and fatals %q; want skips %q only", fake.skips, fake.errors, fake.fatals, tt.wantSkips)
+			}
+		})
+	}
+}
+
+func TestSkipIfAny(t *testing.T) {
+	reasons := map[string]testerr.Want{
+		"docker unavailable": testerr.Contains("docker.sock"),
+		"IPv6 unavailable":   testerr.Contains("address family not supported"),
+		"no sockets":         testerr.Contains("socket"),
+	}
+
+	tests := []struct {
+		name      string
+		got       error
+		wantSkips []string
+	}{
+		{
+			name: "nil error",
+		},
+		{
+			name: "no condition matches",
+			got:  io.EOF,
+		},
+		{
+			name:      "single condition",
+			got:       errors.New("dial tcp [::1]:80: address family not supported by protocol"),
+			wantSkips: []string{`IPv6 unavailable: error "dial tcp [::1]:80: address family not supported by protocol" matches containing substring "address family not supported"`},
+		},
+		{
+			name:      "first of multiple conditions",
+			got:       errors.New("dial unix /var/run/docker.sock: connect: no such file"),
+			wantSkips: []string{`docker unavailable: error "dial unix /var/run/docker.sock: connect: no such file" matches containing substring "docker.sock"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := new(fakeTB)
+			returned := fake.run(func(tb testing.TB) {
+				testerr.SkipIfAny(tb, tt.got, reasons)
+			})
+
+			if wantSkip := len(tt.wantSkips) > 0; returned == wantSkip {
+				t.Errorf("SkipIfAny() returned = %t; want test stopped = %t", returned, wantSkip)
+			}
+			if fmt.Sprint(fake.skips) != fmt.Sprint(tt.wantSkips) || len(fake.errors)+len(fake.fatals) != 0 {
+				t.Errorf("SkipIfAny() reported skips %q, errors %q, and fatals %q; want skips %q only", fake.skips, fake.errors, fake.fatals, tt.wantSkips)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package testerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// A reusableWant is a [testerr.Want], constructed once, along with errors that
+// it matches and doesn't match, respectively.
+type reusableWant struct {
+	name              string
+	want              testerr.Want
+	matched, mismatch error
+	// allocs is the number of allocations permitted, per call of either
+	// [testerr.Diff] or [testerr.Matches], with the matched error. It is
+	// negative if unbounded, which is only the case for Wants that depend on
+	// reflection by other packages.
+	allocs int
+}
+
+// messageAllocs is the number of allocations made by each call to the Error()
+// method of a `got` error, which testerr makes on a new goroutine.
+const messageAllocs = 2
+
+// jsonCodeError is an error carrying a JSON body, with an exported field so it
+// can be compared by [testerr.EqualsCmp] without options.
+type jsonCodeError struct{ Code int }
+
+func (e jsonCodeError) Error() string { return `{"code": 42}` }
+
+// reusableWants returns a [reusableWant] for every [testerr.Want] in the
+// package other than those that require files or processes, i.e.
+// [testerr.MatchesGolden], [testerr.ExitCode], and [testerr.Exit].
+func reusableWants() []reusableWant {
+	errUhOh := errors.New("uh oh")
+	errOther := errors.New("something else")
+	wrapped := fmt.Errorf("wrapped(%w)", errUhOh)
+	wrappedType := fmt.Errorf("wrapped(%w)", myError{42})
+	joined := errors.Join(errUhOh, io.EOF)
+
+	return []reusableWant{
+		{name: "NilWant", want: nil, matched: nil, mismatch: errUhOh},
+		{name: "Nil", want: testerr.Nil(), matched: nil, mismatch: errUhOh},
+		{name: "NotTypedNil", want: testerr.NotTypedNil(), matched: errUhOh, mismatch: (*fs.PathError)(nil)},
+		{name: "Predicate", want: testerr.Predicate("uh oh", testerr.MatchFunc(testerr.Is(errUhOh))), matched: wrapped, mismatch: errOther},
+		{name: "Is", want: testerr.Is(errUhOh), matched: wrapped, mismatch: errOther},
+		{name: "IsAll", want: testerr.IsAll(errUhOh, io.EOF), matched: joined, mismatch: errUhOh},
+		{name: "IsType", want: testerr.IsType[myError](), matched: wrappedType, mismatch: errOther},
+		{name: "As", want: testerr.As(func(myError) string { return "" }), matched: wrappedType, mismatch: errOther},
+		{name: "Implements", want: testerr.Implements[interface{ Timeout() bool }](nil), matched: &testerr.FakeNetError{}, mismatch: errOther},
+		{name: "Equals", want: testerr.Equals(errUhOh), matched: errUhOh, mismatch: errOther},
+		{name: "EqualsCmp", want: testerr.EqualsCmp(jsonCodeError{42}), matched: jsonCodeError{42}, mismatch: jsonCodeError{0}, allocs: -1},
+		{name: "HasCode", want: testerr.HasCode(503), matched: fmt.Errorf("calling: %w", &statusCodeError{503}), mismatch: &statusCodeError{500}},
+		{name: "Contains", want: testerr.Contains("uh"), matched: errUhOh, mismatch: errOther, allocs: messageAllocs},
+		{name: "ContainsFold", want: testerr.ContainsFold("UH"), matched: errUhOh, mismatch: errOther, allocs: messageAllocs},
+		{name: "ContainsWith", want: testerr.ContainsWith("uh\t oh", testerr.CollapseSpace()), matched: errUhOh, mismatch: errOther, allocs: messageAllocs},
+		{name: "ContainsAll", want: testerr.ContainsAll("uh", "oh"), matched: errUhOh, mismatch: errOther, allocs: messageAllocs},
+		{name: "ContainsAny", want: testerr.ContainsAny("x", "oh"), matched: errUhOh, mismatch: errOther, allocs: messageAllocs},
+		{name: "HasPrefix", want: testerr.HasPrefix("uh"), matched: errUhOh, mismatch: errOther, allocs: messageAllocs},
+		{name: "HasSuffix", want: testerr.HasSuffix("oh"), matched: errUhOh, mismatch: errOther, allocs: messageAllocs},
+		{name: "MessageIs", want: testerr.MessageIs("uh oh"), matched: errUhOh, mismatch: errOther, allocs: messageAllocs},
+		{name: "MatchesRegexp", want: testerr.MatchesRegexp(`^uh`), matched: errUhOh, mismatch: errOther, allocs: messageAllocs},
+		{name: "MatchesCompiledRegexp", want: testerr.MatchesCompiledRegexp(regexp.MustCompile(`oh$`)), matched: errUhOh, mismatch: errOther, allocs: messageAllocs},
+		{name: "MatchesFormat", want: testerr.MatchesFormat("uh %s"), matched: errUhOh, mismatch: errOther, allocs: messageAllocs},
+		// Formatting inherently allocates, as does the synthesis of an error
+		// from the output.
+		{name: "Formats", want: testerr.Formats("%+v", testerr.Contains("uh")), matched: errUhOh, mismatch: errOther, allocs: 3 + messageAllocs},
+		// As do the application of rules and the synthesis of a normalized
+		// error.
+		{name: "Normalize", want: testerr.Normalize(testerr.MessageIs("uh oh"), testerr.ReplaceHex(4)), matched: errUhOh, mismatch: errOther, allocs: 4 + 2*messageAllocs},
+		{name: "JSONMessage", want: testerr.JSONMessage(map[string]any{"code": 42}), matched: jsonCodeError{42}, mismatch: errOther, allocs: -1},
+		{name: "All", want: testerr.All(testerr.Is(errUhOh), testerr.Contains("uh")), matched: errUhOh, mismatch: errOther, allocs: messageAllocs},
+		{name: "Any", want: testerr.Any(testerr.Is(errOther), testerr.Is(errUhOh)), matched: errUhOh, mismatch: io.EOF},
+		{name: "Not", want: testerr.Not(testerr.Is(errOther)), matched: errUhOh, mismatch: errOther},
+		{name: "Named", want: testerr.Named("uh oh", testerr.Is(errUhOh)), matched: wrapped, mismatch: errOther},
+		{name: "Map", want: testerr.Map(func(err error) (error, string) { return errors.Unwrap(err), "Unwrap()" }, testerr.Equals(errUhOh)), matched: wrapped, mismatch: errUhOh},
+		{name: "ByGOOS", want: testerr.ByGOOS(nil, testerr.Is(errUhOh)), matched: wrapped, mismatch: errOther},
+		{name: "If", want: testerr.If(true, testerr.Is(errUhOh), nil), matched: wrapped, mismatch: errOther},
+		{name: "Joined", want: testerr.Joined(testerr.Is(errUhOh), testerr.Is(io.EOF)), matched: joined, mismatch: errors.Join(io.EOF, errUhOh)},
+		{name: "JoinedUnordered", want: testerr.JoinedUnordered(testerr.Is(io.EOF), testerr.Is(errUhOh)), matched: joined, mismatch: errors.Join(io.EOF, io.EOF)},
+		{name: "JoinedLen", want: testerr.JoinedLen(2), matched: joined, mismatch: errUhOh},
+		{name: "JoinedLenAtLeast", want: testerr.JoinedLenAtLeast(2), matched: joined, mismatch: errors.Join(errUhOh)},
+		{name: "JoinedLenAtMost", want: testerr.JoinedLenAtMost(2), matched: joined, mismatch: errors.Join(errUhOh, errOther, io.EOF)},
+		{name: "ChainOf", want: testerr.ChainOf(testerr.HasPrefix("wrapped"), testerr.Equals(errUhOh)), matched: wrapped, mismatch: errUhOh, allocs: messageAllocs},
+		{name: "ExactChainOf", want: testerr.ExactChainOf(testerr.HasPrefix("wrapped"), testerr.Equals(errUhOh)), matched: wrapped, mismatch: fmt.Errorf("x: %w", wrapped), allocs: messageAllocs},
+		{name: "Unwrapped", want: testerr.Unwrapped(1, testerr.Equals(errUhOh)), matched: wrapped, mismatch: errUhOh},
+		// The annotation is checked as a synthesized error.
+		{name: "Wraps", want: testerr.Wraps(testerr.HasPrefix("wrapped"), testerr.Equals(errUhOh)), matched: wrapped, mismatch: errUhOh, allocs: 1 + 3*messageAllocs},
+		{name: "CountMatching", want: testerr.CountMatching(testerr.Is(errUhOh), 1), matched: errUhOh, mismatch: errOther},
+		{name: "Count", want: testerr.Count(errUhOh, 1), matched: wrapped, mismatch: errors.Join(errUhOh, errUhOh)},
+		{name: "AnywhereInTree", want: testerr.AnywhereInTree(testerr.MessageIs("uh oh")), matched: fmt.Errorf("x: %w", wrapped), mismatch: errOther, allocs: 3 * messageAllocs},
+		{name: "Canceled", want: testerr.Canceled(testerr.Is(errUhOh)), matched: errors.Join(context.Canceled, errUhOh), mismatch: context.Canceled},
+		{name: "DeadlineExceeded", want: testerr.DeadlineExceeded(testerr.Is(context.DeadlineExceeded)), matched: context.DeadlineExceeded, mismatch: context.Canceled},
+		{name: "HasStack", want: testerr.HasStack(), matched: originHelper(), mismatch: errOther},
+		// StackTrace() methods are called via reflect.Value.Call().
+		{name: "StackContains", want: testerr.StackContains("originHelper"), matched: originHelper(), mismatch: errOther, allocs: -1},
+	}
+}
+
+// benchmarkDiff benchmarks [testerr.Diff] of the [reusableWant] named `name`,
+// with sub-benchmarks for its matched and mismatched errors. The table is
+// shared with the allocation tests so every entry MUST also have a
+// BenchmarkDiff_<name> function that calls benchmarkDiff, which is checked by
+// TestBenchmarkDiffPerWant.
+func benchmarkDiff(b *testing.B, name string) {
+	wants := reusableWants()
+	i := slices.IndexFunc(wants, func(w reusableWant) bool { return w.name == name })
+	if i == -1 {
+		b.Fatalf("no reusableWant named %q", name)
+	}
+	w := wants[i]
+
+	for _, c := range []struct {
+		name string
+		err  error
+	}{
+		{"matched", w.matched},
+		{"mismatch", w.mismatch},
+	} {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				testerr.Diff(c.err, w.want)
+			}
+		})
+	}
+}
+
+func BenchmarkDiff_NilWant(b *testing.B)               { benchmarkDiff(b, "NilWant") }
+func BenchmarkDiff_Nil(b *testing.B)                   { benchmarkDiff(b, "Nil") }
+func BenchmarkDiff_NotTypedNil(b *testing.B)           { benchmarkDiff(b, "NotTypedNil") }
+func BenchmarkDiff_Predicate(b *testing.B)             { benchmarkDiff(b, "Predicate") }
+func BenchmarkDiff_Is(b *testing.B)                    { benchmarkDiff(b, "Is") }
+func BenchmarkDiff_IsAll(b *testing.B)                 { benchmarkDiff(b, "IsAll") }
+func BenchmarkDiff_IsType(b *testing.B)                { benchmarkDiff(b, "IsType") }
+func BenchmarkDiff_As(b *testing.B)                    { benchmarkDiff(b, "As") }
+func BenchmarkDiff_Implements(b *testing.B)            { benchmarkDiff(b, "Implements") }
+func BenchmarkDiff_Equals(b *testing.B)                { benchmarkDiff(b, "Equals") }
+func BenchmarkDiff_EqualsCmp(b *testing.B)             { benchmarkDiff(b, "EqualsCmp") }
+func BenchmarkDiff_HasCode(b *testing.B)               { benchmarkDiff(b, "HasCode") }
+func BenchmarkDiff_Contains(b *testing.B)              { benchmarkDiff(b, "Contains") }
+func BenchmarkDiff_ContainsFold(b *testing.B)          { benchmarkDiff(b, "ContainsFold") }
+func BenchmarkDiff_ContainsWith(b *testing.B)          { benchmarkDiff(b, "ContainsWith") }
+func BenchmarkDiff_ContainsAll(b *testing.B)           { benchmarkDiff(b, "ContainsAll") }
+func BenchmarkDiff_ContainsAny(b *testing.B)           { benchmarkDiff(b, "ContainsAny") }
+func BenchmarkDiff_HasPrefix(b *testing.B)             { benchmarkDiff(b, "HasPrefix") }
+func BenchmarkDiff_HasSuffix(b *testing.B)             { benchmarkDiff(b, "HasSuffix") }
+func BenchmarkDiff_MessageIs(b *testing.B)             { benchmarkDiff(b, "MessageIs") }
+func BenchmarkDiff_MatchesRegexp(b *testing.B)         { benchmarkDiff(b, "MatchesRegexp") }
+func BenchmarkDiff_MatchesCompiledRegexp(b *testing.B) { benchmarkDiff(b, "MatchesCompiledRegexp") }
+func BenchmarkDiff_MatchesFormat(b *testing.B)         { benchmarkDiff(b, "MatchesFormat") }
+func BenchmarkDiff_Formats(b *testing.B)               { benchmarkDiff(b, "Formats") }
+func BenchmarkDiff_Normalize(b *testing.B)             { benchmarkDiff(b, "Normalize") }
+func BenchmarkDiff_JSONMessage(b *testing.B)           { benchmarkDiff(b, "JSONMessage") }
+func BenchmarkDiff_All(b *testing.B)                   { benchmarkDiff(b, "All") }
+func BenchmarkDiff_Any(b *testing.B)                   { benchmarkDiff(b, "Any") }
+func BenchmarkDiff_Not(b *testing.B)                   { benchmarkDiff(b, "Not") }
+func BenchmarkDiff_Named(b *testing.B)                 { benchmarkDiff(b, "Named") }
+func BenchmarkDiff_Map(b *testing.B)                   { benchmarkDiff(b, "Map") }
+func BenchmarkDiff_ByGOOS(b *testing.B)                { benchmarkDiff(b, "ByGOOS") }
+func BenchmarkDiff_If(b *testing.B)                    { benchmarkDiff(b, "If") }
+func BenchmarkDiff_Joined(b *testing.B)                { benchmarkDiff(b, "Joined") }
+func BenchmarkDiff_JoinedUnordered(b *testing.B)       { benchmarkDiff(b, "JoinedUnordered") }
+func BenchmarkDiff_JoinedLen(b *testing.B)             { benchmarkDiff(b, "JoinedLen") }
+func BenchmarkDiff_JoinedLenAtLeast(b *testing.B)      { benchmarkDiff(b, "JoinedLenAtLeast") }
+func BenchmarkDiff_JoinedLenAtMost(b *testing.B)       { benchmarkDiff(b, "JoinedLenAtMost") }
+func BenchmarkDiff_ChainOf(b *testing.B)               { benchmarkDiff(b, "ChainOf") }
+func BenchmarkDiff_ExactChainOf(b *testing.B)          { benchmarkDiff(b, "ExactChainOf") }
+func BenchmarkDiff_Unwrapped(b *testing.B)             { benchmarkDiff(b, "Unwrapped") }
+func BenchmarkDiff_Wraps(b *testing.B)                 { benchmarkDiff(b, "Wraps") }
+func BenchmarkDiff_CountMatching(b *testing.B)         { benchmarkDiff(b, "CountMatching") }
+func BenchmarkDiff_Count(b *testing.B)                 { benchmarkDiff(b, "Count") }
+func BenchmarkDiff_AnywhereInTree(b *testing.B)        { benchmarkDiff(b, "AnywhereInTree") }
+func BenchmarkDiff_Canceled(b *testing.B)              { benchmarkDiff(b, "Canceled") }
+func BenchmarkDiff_DeadlineExceeded(b *testing.B)      { benchmarkDiff(b, "DeadlineExceeded") }
+func BenchmarkDiff_HasStack(b *testing.B)              { benchmarkDiff(b, "HasStack") }
+func BenchmarkDiff_StackContains(b *testing.B)         { benchmarkDiff(b, "StackContains") }
+
+func TestBenchmarkDiffPerWant(t *testing.T) {
+	f, err := parser.ParseFile(token.NewFileSet(), "bench_test.go", nil, parser.SkipObjectResolution)
+	if err != nil {
+		t.Fatalf("parser.ParseFile(bench_test.go) error %v", err)
+	}
+	benchmarked := make(map[string]bool)
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok {
+			if name, ok := strings.CutPrefix(fn.Name.Name, "BenchmarkDiff_"); ok {
+				benchmarked[name] = true
+			}
+		}
+	}
+	for _, w := range reusableWants() {
+		if !benchmarked[w.name] {
+			t.Errorf("no BenchmarkDiff_%s() for reusableWant %q", w.name, w.name)
+		}
+	}
+}
+
+// BenchmarkMatches demonstrates that [testerr.Matches] doesn't construct diffs
+// on success, even for [testerr.Want]s such as [testerr.Any] and [testerr.Not]
+// that would otherwise do so for their failing inner [testerr.Want]s.
+func BenchmarkMatches(b *testing.B) {
+	for _, w := range reusableWants() {
+		b.Run(w.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				testerr.Matches(w.matched, w.want)
+			}
+		})
+	}
+}
+
+func TestMatchesAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("race detector results in allocations")
+	}
+	for _, w := range reusableWants() {
+		t.Run(w.name, func(t *testing.T) {
+			if !testerr.Matches(w.matched, w.want) {
+				t.Fatalf("Matches(%v, [reused %s]) got false; want true", w.matched, w.name)
+			}
+			if testerr.Matches(w.mismatch, w.want) {
+				t.Fatalf("Matches(%v, [reused %s]) got true; want false", w.mismatch, w.name)
+			}
+			if w.allocs < 0 {
+				return
+			}
+			if n := testing.AllocsPerRun(100, func() { testerr.Matches(w.matched, w.want) }); n > float64(w.allocs) {
+				t.Errorf("Matches(%v, [reused %s]) allocated %v times per run; want <= %d", w.matched, w.name, n, w.allocs)
+			}
+		})
+	}
+}
+
+// TestDiffAllocs is the equivalent of [TestMatchesAllocs] for [testerr.Diff],
+// which MUST only construct a diff once a mismatch is established.
+func TestDiffAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("race detector results in allocations")
+	}
+	for _, w := range reusableWants() {
+		t.Run(w.name, func(t *testing.T) {
+			if diff := testerr.Diff(w.matched, w.want); diff != "" {
+				t.Fatalf("Diff(%v, [reused %s]) %s", w.matched, w.name, diff)
+			}
+			if testerr.Diff(w.mismatch, w.want) == "" {
+				t.Fatalf("Diff(%v, [reused %s]) got empty diff", w.mismatch, w.name)
+			}
+			if w.allocs < 0 {
+				return
+			}
+			if n := testing.AllocsPerRun(100, func() { testerr.Diff(w.matched, w.want) }); n > float64(w.allocs) {
+				t.Errorf("Diff(%v, [reused %s]) allocated %v times per run; want <= %d", w.matched, w.name, n, w.allocs)
+			}
+		})
+	}
+}
+
+func TestConcurrentReuse(t *testing.T) {
+	// This test is only meaningful with the race detector enabled.
+	const goroutines = 8
+
+	for _, w := range reusableWants() {
+		t.Run(w.name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			for range goroutines {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range 100 {
+						if diff := testerr.Diff(w.matched, w.want); diff != "" {
+							t.Errorf("Diff(%v, [reused %s]) %s", w.matched, w.name, diff)
+						}
+						if testerr.Diff(w.mismatch, w.want) == "" {
+							t.Errorf("Diff(%v, [reused %s]) got empty diff", w.mismatch, w.name)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package testerr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ChainOf checks the successive layers of the `got` error, as returned by
+// repeated calls to [errors.Unwrap], against the `wants`. Layer zero is the
+// `got` error itself, which is matched against `wants[0]`, and so on. The
+// chain MAY have more layers than there are `wants`; see [ExactChainOf] to
+// disallow this. As layers are never nil, a nil [Want] is rejected with a
+// diff, as is an empty chain, as with [All].
+//
+// Only linear chains are supported so if a layer that needs to be unwrapped
+// has an `Unwrap() []error` method, the diff says so. On mismatch, the diff
+// identifies the first mismatched layer and lists the layers walked.
+func ChainOf(wants ...Want) Want {
+	return chainOf("ChainOf", false, wants)
+}
+
+// ExactChainOf is equivalent to [ChainOf] except that the chain MUST NOT have
+// any layers beyond those matched by the `wants`.
+func ExactChainOf(wants ...Want) Want {
+	return chainOf("ExactChainOf", true, wants)
+}
+
+func chainOf(fn string, exact bool, wants []Want) Want {
+	desc := fmt.Sprintf("chain of errors unwrapping as %s", describeAll(wants))
+	if exact {
+		desc = fmt.Sprintf("chain of exactly %d error(s) unwrapping as %s", len(wants), describeAll(wants))
+	}
+
+	return &described{
+		desc: desc,
+		diff: func(got error) string {
+			if len(wants) == 0 {
+				return DiffMessage(got, "%s() of at least one expectation", fn)
+			}
+			for i, w := range wants {
+				if isNil(w) {
+					return DiffMessage(got, "%s() of non-nil Wants; got nil at index %d", fn, i)
+				}
+			}
+
+			var walked []error
+			chain := func() string { return renderChain(walked) }
+
+			err := got
+			for i, w := range wants {
+				if err == nil {
+					return DiffMessage(got, "%s; chain ended after %d layer(s): %s", desc, i, chain())
+				}
+				walked = append(walked, err)
+				if d := w.ErrDiff(err); d != "" {
+					return DiffMessage(
+						got, "%s; layer %d mismatched in chain %s:\n%s",
+						desc, i, chain(), indexedDiff(i, d),
+					)
+				}
+				if i == len(wants)-1 && !exact {
+					break
+				}
+				if _, ok := err.(interface{ Unwrap() []error }); ok {
+					return DiffMessage(
+						got, "%s; layer %d (%T) has Unwrap() []error but %s() only supports linear chains: %s",
+						desc, i, err, fn, chain(),
+					)
+				}
+				err = unwrapOnce(err)
+			}
+
+			if exact && err != nil {
+				walked = append(walked, err)
+				return DiffMessage(got, "%s; chain has further layer(s): %s", desc, chain())
+			}
+			return ""
+		},
+	}
+}
+
+// unwrapOnce is equivalent to [errors.Unwrap].
+func unwrapOnce(err error) error {
+	u, ok := err.(interface{ Unwrap() error })
+	if !ok {
+		return nil
+	}
+	return u.Unwrap()
+}
+
+// renderChain returns the quoted messages of the errors, each prefixed by its
+// index and separated by arrows.
+func renderChain(errs []error) string {
+	parts := make([]string, len(errs))
+	for i, e := range errs {
+		parts[i] = fmt.Sprintf("[%d] %s", i, quoteMessage(e))
+	}
+	return strings.Join(parts, " -> ")
+}
+
+// Unwrapped checks the error returned by calling [errors.Unwrap] on the `got`
+// error `n` times, against `want`, which is treated in the same manner as by
+// [Diff]. `Unwrapped(0, want)` is therefore equivalent to `want`, and a nil
+// [Want] checks that the chain has exactly `n` layers. A negative `n` is
+// rejected with a diff.
+//
+// Only the final call to [errors.Unwrap] MAY return nil; if the chain ends
+// earlier, the diff reports the layers walked. As with [ChainOf], only linear
+// chains are supported so unwrapping an error with an `Unwrap() []error`
+// method results in a diff.
+func Unwrapped(n int, want Want) Want {
+	desc := fmt.Sprintf("error unwrapping, after %d layer(s), to %s", n, Describe(want))
+
+	return &described{
+		desc: desc,
+		diff: func(got error) string {
+			if n < 0 {
+				return DiffMessage(got, "Unwrapped() of non-negative depth; got %d", n)
+			}
+
+			var walked []error
+			err := got
+			for i := range n {
+				if err == nil {
+					return DiffMessage(got, "%s; chain ended after %d layer(s): %s", desc, i, renderChain(walked))
+				}
+				walked = append(walked, err)
+				if _, ok := err.(interface{ Unwrap() []error }); ok {
+					return DiffMessage(
+						got, "%s; layer %d (%T) has Unwrap() []error but Unwrapped() only supports linear chains: %s",
+						desc, i, err, renderChain(walked),
+					)
+				}
+				err = unwrapOnce(err)
+			}
+
+			d := Diff(err, want)
+			if d == "" || n == 0 {
+				return d
+			}
+			if err != nil {
+				walked = append(walked, err)
+			}
+			return DiffMessage(
+				got, "%s; layer %d mismatched in chain %s:\n%s",
+				desc, n, renderChain(walked), indexedDiff(n, d),
+			)
+		},
+	}
+}
+
+// Wraps checks the annotation added by the outermost layer of the `got` error
+// against `annotation`, and the error that it wraps, as returned by
+// [errors.Unwrap], against `cause`. This allows a layer's own contribution to be
+// checked without also matching the message of the cause, which [Contains]
+// would see embedded in the whole message.
+//
+// If the `got` error's string ends with that of the cause, as is conventional
+// with [fmt.Errorf] and a trailing %w, the annotation is the remaining prefix,
+// including any separator, e.g. `sync store "x": `. Otherwise `annotation` is
+// applied to the entire string, which the diff notes. In both cases, the
+// annotation is checked as an error carrying only the message, so [Want]s that
+// inspect the tree (e.g. [Is]) are of no use.
+//
+// As with [Unwrapped], only linear chains are supported, and a `got` error that
+// doesn't unwrap to a non-nil error always results in a diff. Both [Want]s
+// MUST be non-nil, otherwise a diff is returned.
+func Wraps(annotation, cause Want) Want {
+	desc := fmt.Sprintf("error wrapping, with annotation %s, cause %s", Describe(annotation), Describe(cause))
+
+	return &described{
+		desc: desc,
+		diff: func(got error) string {
+			if isNil(annotation) || isNil(cause) {
+				return DiffMessage(got, "Wraps() of non-nil Wants")
+			}
+			if got == nil {
+				return DiffMessage(got, "%s", desc)
+			}
+
+			inner := unwrapOnce(got)
+			if inner == nil {
+				switch got.(type) {
+				case interface{ Unwrap() error }:
+					return DiffMessage(got, "%s; got %T whose Unwrap() returned nil", desc, got)
+				case interface{ Unwrap() []error }:
+					return DiffMessage(got, "%s; got %T with Unwrap() []error but Wraps() only supports linear chains", desc, got)
+				default:
+					return DiffMessage(got, "%s; got %T without Unwrap() error", desc, got)
+				}
+			}
+
+			outerMsg, ok := message(got)
+			if !ok {
+				return DiffMessage(got, "%s", desc)
+			}
+			innerMsg, ok := message(inner)
+			if !ok {
+				return DiffMessage(got, "%s; wrapped %T whose %s", desc, inner, innerMsg)
+			}
+
+			var failed []string
+			ann, embedded := strings.CutSuffix(outerMsg, innerMsg)
+			if d := Diff(errors.New(ann), annotation); d != "" {
+				if !embedded {
+					d += " (message doesn't end with the wrapped error's so checked in its entirety)"
+				}
+				failed = append(failed, "\tannotation: "+strings.ReplaceAll(d, "\n", "\n\t"))
+			}
+			if d := Diff(inner, cause); d != "" {
+				failed = append(failed, "\tcause: "+strings.ReplaceAll(d, "\n", "\n\t"))
+			}
+			if len(failed) == 0 {
+				return ""
+			}
+			return DiffMessage(got, "%s; mismatched:\n%s", desc, strings.Join(failed, "\n"))
+		},
+	}
+}
@@ -0,0 +1,178 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+func ExampleChainOf() {
+	err := fmt.Errorf("handler: %w", fmt.Errorf("store: %w", io.EOF))
+
+	for _, tt := range []struct {
+		err  error
+		want testerr.Want
+	}{
+		{
+			err: err,
+			want: testerr.ChainOf(
+				testerr.HasPrefix("handler: "),
+				testerr.HasPrefix("store: "),
+				testerr.Equals(io.EOF),
+			),
+		},
+		{
+			// Deeper layers are allowed by ChainOf()...
+			err:  err,
+			want: testerr.ChainOf(testerr.HasPrefix("handler: ")),
+		},
+		{
+			// ... but not by ExactChainOf().
+			err:  err,
+			want: testerr.ExactChainOf(testerr.HasPrefix("handler: ")),
+		},
+		{
+			err: err,
+			want: testerr.ChainOf(
+				testerr.HasPrefix("handler: "),
+				testerr.Equals(io.EOF),
+			),
+		},
+		{
+			err: err,
+			want: testerr.ChainOf(
+				testerr.HasPrefix("handler: "),
+				testerr.HasPrefix("store: "),
+				testerr.Equals(io.EOF),
+				testerr.Equals(io.EOF),
+			),
+		},
+		{
+			err: fmt.Errorf("handler: %w", errors.Join(io.EOF, io.ErrClosedPipe)),
+			want: testerr.ChainOf(
+				testerr.HasPrefix("handler: "),
+				testerr.Contains("EOF"),
+				testerr.Equals(io.EOF),
+			),
+		},
+	} {
+		printDiff(tt.err, tt.want)
+	}
+
+	// Output:
+	// <empty>
+	// <empty>
+	// got error handler: store: EOF; want chain of exactly 1 error(s) unwrapping as [message with prefix "handler: "]; chain has further layer(s): [0] "handler: store: EOF" -> [1] "store: EOF"
+	// got error handler: store: EOF; want chain of errors unwrapping as [message with prefix "handler: "; == EOF]; layer 1 mismatched in chain [0] "handler: store: EOF" -> [1] "store: EOF":
+	// 	[1] got error store: EOF; want == EOF
+	// got error handler: store: EOF; want chain of errors unwrapping as [message with prefix "handler: "; message with prefix "store: "; == EOF; == EOF]; chain ended after 3 layer(s): [0] "handler: store: EOF" -> [1] "store: EOF" -> [2] "EOF"
+	// got error handler: EOF\nio: read/write on closed pipe; want chain of errors unwrapping as [message with prefix "handler: "; containing substring "EOF"; == EOF]; layer 1 (*errors.joinError) has Unwrap() []error but ChainOf() only supports linear chains: [0] "handler: EOF\nio: read/write on closed pipe" -> [1] "EOF\nio: read/write on closed pipe"
+}
+
+func TestChainOfEmpty(t *testing.T) {
+	for _, err := range []error{nil, io.EOF, fmt.Errorf("wrapped: %w", io.EOF)} {
+		for name, want := range map[string]testerr.Want{
+			"ChainOf":      testerr.ChainOf(),
+			"ExactChainOf": testerr.ExactChainOf(),
+		} {
+			if diff := testerr.Diff(err, want); !strings.HasSuffix(diff, "; want "+name+"() of at least one expectation") {
+				t.Errorf("Diff(%v, %s()) got %q; want rejection of empty chain", err, name, diff)
+			}
+		}
+	}
+}
+
+func ExampleUnwrapped() {
+	err := fmt.Errorf("handler: %w", fmt.Errorf("store: %w", io.EOF))
+
+	for _, tt := range []struct {
+		err  error
+		want testerr.Want
+	}{
+		{err, testerr.Unwrapped(2, testerr.Equals(io.EOF))},
+		{err, testerr.Unwrapped(0, testerr.HasPrefix("handler: "))},
+		{err, testerr.Unwrapped(3, nil)}, // i.e. exactly 3 layers
+		{err, testerr.Unwrapped(1, testerr.Equals(io.EOF))},
+		{err, testerr.Unwrapped(5, testerr.Equals(io.EOF))},
+		{err, testerr.Unwrapped(-1, testerr.Equals(io.EOF))},
+		{err, testerr.Unwrapped(0, testerr.Equals(io.EOF))},
+		{io.EOF, testerr.Unwrapped(1, testerr.Equals(io.EOF))},
+		{
+			fmt.Errorf("handler: %w", errors.Join(io.EOF, io.ErrClosedPipe)),
+			testerr.Unwrapped(2, testerr.Equals(io.EOF)),
+		},
+	} {
+		printDiff(tt.err, tt.want)
+	}
+
+	// Output:
+	// <empty>
+	// <empty>
+	// <empty>
+	// got error handler: store: EOF; want error unwrapping, after 1 layer(s), to == EOF; layer 1 mismatched in chain [0] "handler: store: EOF" -> [1] "store: EOF":
+	// 	[1] got error store: EOF; want == EOF
+	// got error handler: store: EOF; want error unwrapping, after 5 layer(s), to == EOF; chain ended after 3 layer(s): [0] "handler: store: EOF" -> [1] "store: EOF" -> [2] "EOF"
+	// got error handler: store: EOF; want Unwrapped() of non-negative depth; got -1
+	// got error handler: store: EOF; want == EOF
+	// got error EOF; want error unwrapping, after 1 layer(s), to == EOF; layer 1 mismatched in chain [0] "EOF":
+	// 	[1] got error <nil>; want == EOF
+	// got error handler: EOF\nio: read/write on closed pipe; want error unwrapping, after 2 layer(s), to == EOF; layer 1 (*errors.joinError) has Unwrap() []error but Unwrapped() only supports linear chains: [0] "handler: EOF\nio: read/write on closed pipe" -> [1] "EOF\nio: read/write on closed pipe"
+}
+
+// retryError wraps an error with a message that doesn't embed the cause's.
+type retryError struct {
+	attempts int
+	err      error
+}
+
+func (e *retryError) Error() string { return fmt.Sprintf("gave up after %d attempts", e.attempts) }
+func (e *retryError) Unwrap() error { return e.err }
+
+func ExampleWraps() {
+	const name = "users"
+	err := fmt.Errorf("sync store %q: %w", name, io.ErrUnexpectedEOF)
+	chain := fmt.Errorf("handler: %w", err)
+	retry := &retryError{attempts: 3, err: io.EOF}
+
+	for _, tt := range []struct {
+		err  error
+		want testerr.Want
+	}{
+		{err, testerr.Wraps(testerr.MessageIs(`sync store "users": `), testerr.Is(io.ErrUnexpectedEOF))},
+		{err, testerr.Wraps(testerr.Contains("unexpected"), testerr.Is(io.ErrUnexpectedEOF))},
+		{
+			chain,
+			testerr.Wraps(
+				testerr.MessageIs("handler: "),
+				testerr.Wraps(testerr.HasPrefix("sync store"), testerr.Is(io.ErrUnexpectedEOF)),
+			),
+		},
+		{chain, testerr.Wraps(testerr.MessageIs("handler: "), testerr.Wraps(testerr.Contains("users"), testerr.Is(io.EOF)))},
+		{retry, testerr.Wraps(testerr.Contains("3 attempts"), testerr.Is(io.EOF))},
+		{retry, testerr.Wraps(testerr.Contains("5 attempts"), testerr.Is(io.EOF))},
+		{io.EOF, testerr.Wraps(testerr.Contains(""), testerr.Is(io.EOF))},
+		{errors.Join(io.EOF), testerr.Wraps(testerr.Contains(""), testerr.Is(io.EOF))},
+		{err, testerr.Wraps(nil, testerr.Is(io.ErrUnexpectedEOF))},
+	} {
+		printDiff(tt.err, tt.want)
+	}
+
+	// Output:
+	// <empty>
+	// got error sync store "users": unexpected EOF; want error wrapping, with annotation containing substring "unexpected", cause error that Is() unexpected EOF; mismatched:
+	// 	annotation: got error sync store "users": ; want containing substring "unexpected"
+	// <empty>
+	// got error handler: sync store "users": unexpected EOF; want error wrapping, with annotation message "handler: ", cause error wrapping, with annotation containing substring "users", cause error that Is() EOF; mismatched:
+	// 	cause: got error sync store "users": unexpected EOF; want error wrapping, with annotation containing substring "users", cause error that Is() EOF; mismatched:
+	// 		cause: got error unexpected EOF; want error that Is() EOF
+	// <empty>
+	// got error gave up after 3 attempts; want error wrapping, with annotation containing substring "5 attempts", cause error that Is() EOF; mismatched:
+	// 	annotation: got error gave up after 3 attempts; want containing substring "5 attempts" (message doesn't end with the wrapped error's so checked in its entirety)
+	// got error EOF; want error wrapping, with annotation containing substring "", cause error that Is() EOF; got *errors.errorString without Unwrap() error
+	// got error EOF; want error wrapping, with annotation containing substring "", cause error that Is() EOF; got *errors.joinError with Unwrap() []error but Wraps() only supports linear chains
+	// got error sync store "users": unexpected EOF; want Wraps() of non-nil Wants
+}
@@ -0,0 +1,137 @@
+package testerr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// EqualsCmp checks that the `got` error is equal to `want`, as determined by
+// [cmp.Diff] with the provided options. Unlike [Is], the `got` error is not
+// unwrapped. This is useful for errors that aren't comparable or that are
+// recreated on every call (e.g. pointers to structs).
+//
+// [cmp.Diff] panics if the errors have unexported fields and no option, such
+// as [cmp.AllowUnexported] or [cmpopts.IgnoreUnexported], handles them; the
+// panic is instead reported as a diff.
+//
+// [cmpopts.IgnoreUnexported]: https://pkg.go.dev/github.com/google/go-cmp/cmp/cmpopts#IgnoreUnexported
+func EqualsCmp(want error, opts ...cmp.Option) Want {
+	desc := fmt.Sprintf("error equal to %v according to cmp.Diff()", want)
+
+	return &described{
+		desc: desc,
+		diff: func(got error) (diff string) {
+			defer func() {
+				if r := recover(); r != nil {
+					diff = DiffMessage(got, "%s but cmp.Diff() panicked: %v", desc, r)
+				}
+			}()
+
+			if d := cmp.Diff(want, got, opts...); d != "" {
+				return DiffMessage(got, "%s; diff (-want +got):\n%s", desc, d)
+			}
+			return ""
+		},
+	}
+}
+
+// DiffValErr compares both returns of a function of the form `func() (T, error)`
+// in a single step. The `gotErr` is checked against `wantErr` in the same
+// manner as by [Diff]. Only if a nil error was both wanted and got are the
+// values compared, with [cmp.Diff] and the provided options; otherwise the
+// value of `gotVal` is considered meaningless and ignored.
+//
+// A non-zero `wantVal` alongside a non-nil `wantErr` is likely a mistake in the
+// test and is therefore reported, even if the errors match.
+//
+// Each part of the returned diff is on its own line, prefixed by "error:" or
+// "value:" respectively.
+func DiffValErr[T any](gotVal T, gotErr error, wantVal T, wantErr Want, opts ...cmp.Option) string {
+	var parts []string
+	if d := Diff(gotErr, wantErr); d != "" {
+		parts = append(parts, "error: "+d)
+	}
+
+	switch {
+	case !isNil(wantErr):
+		if !reflect.ValueOf(&wantVal).Elem().IsZero() {
+			parts = append(parts, fmt.Sprintf("value: want %+v alongside non-nil error expectation (%s); value is never compared", wantVal, Describe(wantErr)))
+		}
+	case gotErr == nil:
+		if d := cmpDiff(wantVal, gotVal, opts...); d != "" {
+			parts = append(parts, "value: "+d)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// cmpDiff returns [cmp.Diff], prefixed to show its direction, or a description
+// of why it panicked.
+func cmpDiff(want, got any, opts ...cmp.Option) (diff string) {
+	defer func() {
+		if r := recover(); r != nil {
+			diff = fmt.Sprintf("cmp.Diff() panicked: %v", r)
+		}
+	}()
+	if d := cmp.Diff(want, got, opts...); d != "" {
+		return fmt.Sprintf("diff (-want +got):\n%s", d)
+	}
+	return ""
+}
+
+// Expected allows a [Want] to be used in place of an error, typically as a field
+// of a struct, that is compared with [cmp.Diff] or [cmp.Equal] along with the
+// [CmpWants] option. A nil Want expects a nil error, in the same manner as for
+// [Diff].
+type Expected struct {
+	Want Want
+}
+
+var (
+	_ error     = Expected{}
+	_ Describer = Expected{}
+)
+
+// Error returns the [Describe] value of the [Want], allowing it to be reported
+// by [cmp.Diff] on mismatch.
+func (e Expected) Error() string { return Describe(e.Want) }
+
+// ErrDiff is equivalent to [Diff] with the [Want].
+func (e Expected) ErrDiff(got error) string { return Diff(got, e.Want) }
+
+// Describe is equivalent to [Describe] with the [Want].
+func (e Expected) Describe() string { return Describe(e.Want) }
+
+// CmpWants returns a [cmp.Option] that treats every [Expected] error as equal
+// to the error on the other side of the comparison if and only if the latter
+// results in an empty diff from the [Expected.Want]. On mismatch, [cmp.Diff]
+// reports the description of the [Want] against the message of the error.
+//
+// As [cmp] panics if more than one option applies to the same values, and
+// errors with unexported fields otherwise require one, CmpWants also compares
+// all other pairs of non-nil errors in the same manner as
+// [cmpopts.EquateErrors], which MUST NOT be used alongside it.
+//
+// [cmpopts.EquateErrors]: https://pkg.go.dev/github.com/google/go-cmp/cmp/cmpopts#EquateErrors
+func CmpWants() cmp.Option {
+	return cmp.FilterValues(
+		func(x, y error) bool {
+			_, xOK := x.(Expected)
+			_, yOK := y.(Expected)
+			return xOK != yOK || (!xOK && x != nil && y != nil)
+		},
+		cmp.Comparer(func(x, y error) bool {
+			if e, ok := x.(Expected); ok {
+				return e.ErrDiff(y) == ""
+			}
+			if e, ok := y.(Expected); ok {
+				return e.ErrDiff(x) == ""
+			}
+			return errors.Is(x, y) || errors.Is(y, x)
+		}),
+	)
+}
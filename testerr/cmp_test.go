@@ -0,0 +1,326 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+type validationError struct {
+	Field string
+	Code  int
+	Stack []string // not comparable
+}
+
+func (e *validationError) Error() string {
+	return fmt.Sprintf("field %q: code %d", e.Field, e.Code)
+}
+
+// unexportedError is a comparable error with an unexported field.
+type unexportedError struct {
+	Code   int
+	detail string
+}
+
+func (e unexportedError) Error() string { return e.detail }
+
+func TestEqualsCmp(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want testerr.Want
+		// wantDiff is a substring of the expected diff, or empty if none
+		wantDiff string
+	}{
+		{
+			name: "recreated pointer",
+			err:  &validationError{Field: "x", Code: 7},
+			want: testerr.EqualsCmp(&validationError{Field: "x", Code: 7}),
+		},
+		{
+			name:     "field mismatch",
+			err:      &validationError{Field: "x", Code: 8},
+			want:     testerr.EqualsCmp(&validationError{Field: "x", Code: 7}),
+			wantDiff: "Code:  7,",
+		},
+		{
+			name: "ignored field",
+			err:  &validationError{Field: "x", Code: 7, Stack: []string{"main.go:42"}},
+			want: testerr.EqualsCmp(
+				&validationError{Field: "x", Code: 7},
+				cmpopts.IgnoreFields(validationError{}, "Stack"),
+			),
+		},
+		{
+			name:     "not unwrapped",
+			err:      fmt.Errorf("wrapped: %w", &validationError{Field: "x", Code: 7}),
+			want:     testerr.EqualsCmp(&validationError{Field: "x", Code: 7}),
+			wantDiff: "diff (-want +got)",
+		},
+		{
+			name:     "nil error",
+			want:     testerr.EqualsCmp(&validationError{Field: "x", Code: 7}),
+			wantDiff: "diff (-want +got)",
+		},
+		{
+			name:     "unexported fields without option",
+			err:      unexportedError{Code: 1, detail: "a"},
+			want:     testerr.EqualsCmp(unexportedError{Code: 1, detail: "a"}),
+			wantDiff: "but cmp.Diff() panicked: cannot handle unexported field",
+		},
+		{
+			name: "unexported fields with option",
+			err:  unexportedError{Code: 1, detail: "a"},
+			want: testerr.EqualsCmp(unexportedError{Code: 1, detail: "b"}, cmpopts.IgnoreUnexported(unexportedError{})),
+		},
+		{
+			name: "unexported fields allowed",
+			err:  unexportedError{Code: 1, detail: "a"},
+			want: testerr.EqualsCmp(unexportedError{Code: 1, detail: "a"}, cmp.AllowUnexported(unexportedError{})),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := testerr.Diff(tt.err, tt.want)
+			switch {
+			case tt.wantDiff == "" && diff != "":
+				t.Errorf("Diff(%v, %s) %s", tt.err, testerr.Describe(tt.want), diff)
+			case tt.wantDiff != "" && !strings.Contains(diff, tt.wantDiff):
+				t.Errorf("Diff(%v, %s) got diff %q; want containing %q", tt.err, testerr.Describe(tt.want), diff, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func ExampleEqualsCmp() {
+	// EqualsCmp() doesn't unwrap so, to compare a wrapped error, combine it
+	// with As().
+	equalsCmp := testerr.EqualsCmp(&validationError{Field: "user.id", Code: 7})
+	unwrapThenCmp := testerr.As(func(got *validationError) string {
+		if testerr.Diff(got, equalsCmp) != "" {
+			return testerr.Describe(equalsCmp)
+		}
+		return ""
+	})
+
+	for _, err := range []error{
+		fmt.Errorf("validating: %w", &validationError{Field: "user.id", Code: 7}),
+		fmt.Errorf("validating: %w", &validationError{Field: "user.age", Code: 7}),
+		errors.New("something else"),
+	} {
+		if diff := testerr.Diff(err, unwrapThenCmp); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// got error validating: field "user.age": code 7; want error equal to field "user.id": code 7 according to cmp.Diff()
+	// got error something else; want error tree containing type *testerr_test.validationError; found types [*errors.errorString]
+}
+
+func TestDiffValErr(t *testing.T) {
+	type result struct {
+		ID   int
+		Name string
+	}
+	errNotFound := errors.New("not found")
+
+	tests := []struct {
+		name    string
+		gotVal  *result
+		gotErr  error
+		wantVal *result
+		wantErr testerr.Want
+		// wantDiff are substrings of the expected diff, or empty if none
+		wantDiff []string
+	}{
+		{
+			name:    "nil errors and equal values",
+			gotVal:  &result{1, "a"},
+			wantVal: &result{1, "a"},
+		},
+		{
+			name:     "nil errors and value mismatch",
+			gotVal:   &result{1, "a"},
+			wantVal:  &result{1, "b"},
+			wantDiff: []string{"value: diff (-want +got):\n", `-`, `"b"`, `+`, `"a"`},
+		},
+		{
+			name:     "unexpected error",
+			gotVal:   &result{1, "a"},
+			gotErr:   errNotFound,
+			wantVal:  &result{1, "b"},
+			wantDiff: []string{"error: got error not found; want nil"},
+		},
+		{
+			name:     "missing error",
+			gotVal:   &result{1, "a"},
+			wantErr:  testerr.Is(errNotFound),
+			wantDiff: []string{"error: got error <nil>; want error that Is() not found"},
+		},
+		{
+			name:    "matching errors and value ignored",
+			gotVal:  &result{1, "a"},
+			gotErr:  fmt.Errorf("lookup: %w", errNotFound),
+			wantErr: testerr.Is(errNotFound),
+		},
+		{
+			name:    "non-zero value alongside error expectation",
+			gotErr:  errNotFound,
+			wantVal: &result{ID: 1},
+			wantErr: testerr.Is(errNotFound),
+			wantDiff: []string{
+				"value: want &{ID:1 Name:} alongside non-nil error expectation (error that Is() not found); value is never compared",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := testerr.DiffValErr(tt.gotVal, tt.gotErr, tt.wantVal, tt.wantErr)
+			if len(tt.wantDiff) == 0 {
+				if diff != "" {
+					t.Errorf("DiffValErr(…) got %q; want empty", diff)
+				}
+				return
+			}
+			for _, want := range tt.wantDiff {
+				if !strings.Contains(diff, want) {
+					t.Errorf("DiffValErr(…) got %q; want containing %q", diff, want)
+				}
+			}
+		})
+	}
+}
+
+func ExampleDiffValErr() {
+	errNotFound := errors.New("not found")
+	lookup := func(id int) (string, error) {
+		if id == 0 {
+			return "", fmt.Errorf("id %d: %w", id, errNotFound)
+		}
+		return fmt.Sprintf("user-%d", id), nil
+	}
+
+	for _, tt := range []struct {
+		id      int
+		wantVal string
+		wantErr testerr.Want
+	}{
+		{1, "user-1", nil},
+		{0, "", testerr.Is(errNotFound)},
+		{0, "user-0", nil},
+		{2, "", testerr.Is(errNotFound)},
+	} {
+		got, err := lookup(tt.id)
+		if diff := testerr.DiffValErr(got, err, tt.wantVal, tt.wantErr); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// <empty>
+	// error: got error id 0: not found; want nil
+	// error: got error <nil>; want error that Is() not found
+}
+
+func TestCmpWants(t *testing.T) {
+	type (
+		step struct {
+			Name string
+			Err  error
+		}
+		response struct {
+			Steps []step
+			Err   error
+		}
+	)
+	errTimeout := errors.New("timeout")
+
+	tests := []struct {
+		name        string
+		got, want   response
+		wantDiff    bool
+		wantContain []string
+	}{
+		{
+			name: "all matched",
+			got: response{
+				Steps: []step{
+					{"dial", nil},
+					{"read", fmt.Errorf("read: %w", errTimeout)},
+				},
+				Err: fmt.Errorf("request: %w", errTimeout),
+			},
+			want: response{
+				Steps: []step{
+					{"dial", testerr.Expected{}}, // i.e. nil error
+					{"read", testerr.Expected{Want: testerr.Is(errTimeout)}},
+				},
+				Err: testerr.Expected{Want: testerr.Contains("request")},
+			},
+		},
+		{
+			name: "one mismatch",
+			got: response{
+				Steps: []step{{"dial", errors.New("refused")}},
+				Err:   fmt.Errorf("request: %w", errTimeout),
+			},
+			want: response{
+				Steps: []step{{"dial", testerr.Expected{Want: testerr.Is(errTimeout)}}},
+				Err:   testerr.Expected{Want: testerr.Is(errTimeout)},
+			},
+			wantDiff:    true,
+			wantContain: []string{`e"error that Is() timeout"`, `e"refused"`},
+		},
+		{
+			name:     "nil Want against non-nil error",
+			got:      response{Err: errTimeout},
+			want:     response{Err: testerr.Expected{}},
+			wantDiff: true,
+		},
+		{
+			name:     "Want against nil error",
+			got:      response{},
+			want:     response{Err: testerr.Expected{Want: testerr.Is(errTimeout)}},
+			wantDiff: true,
+		},
+		{
+			name: "ordinary errors equated",
+			got:  response{Err: fmt.Errorf("request: %w", errTimeout)},
+			want: response{Err: errTimeout},
+		},
+		{
+			name:     "ordinary errors differ",
+			got:      response{Err: errors.New("timeout")},
+			want:     response{Err: errTimeout},
+			wantDiff: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := cmp.Diff(tt.want, tt.got, testerr.CmpWants())
+			if got := diff != ""; got != tt.wantDiff {
+				t.Fatalf("cmp.Diff(…, CmpWants()) got diff %q; want non-empty = %t", diff, tt.wantDiff)
+			}
+			for _, want := range tt.wantContain {
+				if !strings.Contains(diff, want) {
+					t.Errorf("cmp.Diff(…, CmpWants()) got diff %q; want containing %q", diff, want)
+				}
+			}
+		})
+	}
+}
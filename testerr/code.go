@@ -0,0 +1,95 @@
+package testerr
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// HasCode checks that the first node in the `got` error's tree with a
+// `Code() C` method returns `want`. If no node has such a method, the first
+// with an `ErrorCode() C` method is used instead. Nodes with methods of the
+// same names but different signatures are ignored, and reported in the diff if
+// no node has a suitable method.
+func HasCode[C comparable](want C) Want {
+	desc := fmt.Sprintf("error with code %s", formatCode(want))
+	typ := reflect.TypeFor[C]()
+
+	return &described{
+		desc:    desc,
+		details: treeTypesDetails,
+		diff: func(got error) string {
+			code, node, method, ok := findCode[C](got)
+			if !ok {
+				return DiffMessage(
+					got, "%s; found no Code() %v or ErrorCode() %v method%s; found types %s",
+					desc, typ, typ, wrongCodeSignatures(got), treeTypesList(got),
+				)
+			}
+			if code != want {
+				return DiffMessage(got, "%s; got %s %s", desc, accessor(node, method), formatCode(code))
+			}
+			return ""
+		},
+	}
+}
+
+// findCode returns the code from the first node in the error tree with a
+// `Code() C` method or, failing that, an `ErrorCode() C` method. It also
+// returns the node and the name of the method, from which [accessor] describes
+// the method for diffs.
+func findCode[C any](err error) (_ C, node error, method string, _ bool) {
+	var (
+		code  C
+		found bool
+	)
+	Walk(err, func(n error) bool {
+		if c, ok := n.(interface{ Code() C }); ok {
+			code, node, method, found = c.Code(), n, "Code", true
+		}
+		return !found
+	})
+	if found {
+		return code, node, method, true
+	}
+
+	Walk(err, func(n error) bool {
+		if c, ok := n.(interface{ ErrorCode() C }); ok {
+			code, node, method, found = c.ErrorCode(), n, "ErrorCode", true
+		}
+		return !found
+	})
+	return code, node, method, found
+}
+
+// accessor returns a description of the `method` of `node`, e.g.
+// "(*pkg.Err).Code()".
+func accessor(node error, method string) string {
+	return fmt.Sprintf("(%T).%s()", node, method)
+}
+
+// wrongCodeSignatures returns a description of all nodes in the error tree
+// with `Code()` or `ErrorCode()` methods, which are assumed to have the wrong
+// signature. It returns an empty string if there are none.
+func wrongCodeSignatures(err error) string {
+	var found []string
+	walk(err, func(node error) {
+		for _, name := range []string{"Code", "ErrorCode"} {
+			if m, ok := reflect.TypeOf(node).MethodByName(name); ok {
+				found = append(found, fmt.Sprintf("(%T).%s as %v", node, name, m.Type))
+			}
+		}
+	})
+	if len(found) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (ignored %s)", strings.Join(found, ", "))
+}
+
+// formatCode formats `c` with %q if it is a string, and %v otherwise.
+func formatCode(c any) string {
+	if reflect.ValueOf(c).Kind() == reflect.String {
+		return fmt.Sprintf("%q", c)
+	}
+	return fmt.Sprintf("%v", c)
+}
@@ -0,0 +1,51 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+type stringCodeError struct{ code string }
+
+func (e stringCodeError) Error() string { return "code " + e.code }
+func (e stringCodeError) Code() string  { return e.code }
+
+type statusCodeError struct{ status int }
+
+func (e *statusCodeError) Error() string  { return fmt.Sprintf("status %d", e.status) }
+func (e *statusCodeError) ErrorCode() int { return e.status }
+
+func ExampleHasCode() {
+	for _, tt := range []struct {
+		err  error
+		want testerr.Want
+	}{
+		{stringCodeError{"quota_exceeded"}, testerr.HasCode("quota_exceeded")},
+		{stringCodeError{"not_found"}, testerr.HasCode("quota_exceeded")},
+		{fmt.Errorf("calling: %w", &statusCodeError{503}), testerr.HasCode(503)},
+		{fmt.Errorf("calling: %w", &statusCodeError{500}), testerr.HasCode(503)},
+		// The Code() method returns a string, not an int.
+		{stringCodeError{"503"}, testerr.HasCode(503)},
+		{errors.Join(io.EOF, stringCodeError{"x"}), testerr.HasCode(uint8(42))},
+		{io.EOF, testerr.HasCode("x")},
+	} {
+		if diff := testerr.Diff(tt.err, tt.want); diff != "" {
+			fmt.Println(strings.ReplaceAll(diff, "\n", `\n`))
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// got error code not_found; want error with code "quota_exceeded"; got (testerr_test.stringCodeError).Code() "not_found"
+	// <empty>
+	// got error calling: status 500; want error with code 503; got (*testerr_test.statusCodeError).ErrorCode() 500
+	// got error code 503; want error with code 503; found no Code() int or ErrorCode() int method (ignored (testerr_test.stringCodeError).Code as func(testerr_test.stringCodeError) string); found types [testerr_test.stringCodeError]
+	// got error EOF\ncode x; want error with code 42; found no Code() uint8 or ErrorCode() uint8 method (ignored (testerr_test.stringCodeError).Code as func(testerr_test.stringCodeError) string); found types [*errors.joinError, *errors.errorString, testerr_test.stringCodeError]
+	// got error EOF; want error with code "x"; found no Code() string or ErrorCode() string method; found types [*errors.errorString]
+}
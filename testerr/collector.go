@@ -0,0 +1,151 @@
+package testerr
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// A Collector runs functions in goroutines and records their returned errors,
+// by name, to be checked with [Collector.Diff]. The zero value is ready to use
+// and a Collector MUST NOT be copied after first use. All methods are safe for
+// concurrent use.
+type Collector struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs map[string][]error // only non-nil errors
+}
+
+// Go calls `fn` in a new goroutine and records its returned error under
+// `name`. A panic in `fn` is recovered and recorded as an error instead of
+// crashing the test binary, as is a call to [runtime.Goexit], e.g. via
+// `t.FailNow()`, although the latter can't be stopped from ending the
+// goroutine. Errors recorded under the same name, by either Go or
+// [Collector.FromChan], are combined with [errors.Join], without nesting.
+func (c *Collector) Go(name string, fn func() error) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		returned := false
+		defer func() {
+			if !returned {
+				c.record(name, errGoexit)
+			}
+		}()
+		c.record(name, call(fn))
+		returned = true
+	}()
+}
+
+// errGoexit is recorded by [Collector.Go] for a function that calls
+// [runtime.Goexit].
+var errGoexit = errors.New("runtime.Goexit() called")
+
+// call returns the error returned by `fn` or, if it panics, an error wrapping
+// the recovered value: either the value itself, if it is an error, or a
+// [PanicValue] carrying the stack.
+func call(fn func() error) error {
+	var err error
+	r, stack, panicked := catchPanic(func() { err = fn() })
+	if !panicked {
+		return err
+	}
+	if e, ok := r.(error); ok {
+		return fmt.Errorf("panic: %w", e)
+	}
+	return fmt.Errorf("panic: %w", PanicValue{Value: r, Stack: stack})
+}
+
+// FromChan receives from `ch`, in a new goroutine, until it is closed and
+// records every error under `name`, in the same manner as [Collector.Go]. Nil
+// errors are ignored so an expectation of nil means that no non-nil errors
+// were received.
+func (c *Collector) FromChan(name string, ch <-chan error) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.record(name, nil) // ensure the name is known even if nothing is received
+		for err := range ch {
+			c.record(name, err)
+		}
+	}()
+}
+
+func (c *Collector) record(name string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.errs == nil {
+		c.errs = make(map[string][]error)
+	}
+	if err != nil {
+		c.errs[name] = append(c.errs[name], err)
+	} else if _, ok := c.errs[name]; !ok {
+		c.errs[name] = nil
+	}
+}
+
+// joined returns the sole error in `errs`, if there is exactly one, or
+// otherwise their [errors.Join].
+func joined(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// Wait blocks until all goroutines started by [Collector.Go] and
+// [Collector.FromChan] have returned.
+func (c *Collector) Wait() {
+	c.wg.Wait()
+}
+
+// Diff calls [Collector.Wait] and then checks the error recorded under every
+// name against the respective element of `wants`, in the same manner as by
+// [Diff]. A name without an entry in `wants` is therefore expected to have a
+// nil error. The diff reports each name, in sorted order, for which the check
+// failed, alongside its own diff, out of the total number of names in either.
+// Names in `wants` that were never recorded are also reported.
+func (c *Collector) Diff(wants map[string]Want) string {
+	c.Wait()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := slices.Sorted(keys(c.errs, wants))
+	var failed []string
+	for _, name := range names {
+		errs, ok := c.errs[name]
+		if !ok {
+			failed = append(failed, fmt.Sprintf("\t[%s] no goroutine; want %s", name, Describe(wants[name])))
+			continue
+		}
+		if d := Diff(joined(errs), wants[name]); d != "" {
+			failed = append(failed, fmt.Sprintf("\t[%s] %s", name, strings.ReplaceAll(d, "\n", "\n\t")))
+		}
+	}
+	if len(failed) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d of %d goroutine(s) failed:\n%s", len(failed), len(names), strings.Join(failed, "\n"))
+}
+
+// keys returns an iterator over the deduplicated union of the maps' keys.
+func keys(errs map[string][]error, wants map[string]Want) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for k := range errs {
+			if !yield(k) {
+				return
+			}
+		}
+		for k := range wants {
+			if _, ok := errs[k]; ok {
+				continue
+			}
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
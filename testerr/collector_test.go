@@ -0,0 +1,174 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+func ExampleCollector() {
+	errBusy := errors.New("busy")
+
+	var c testerr.Collector
+	c.Go("ok", func() error { return nil })
+	c.Go("busy", func() error { return fmt.Errorf("worker: %w", errBusy) })
+	c.Go("eof", func() error { return io.EOF })
+	c.Go("panics", func() error { panic("oops") })
+
+	fmt.Println(c.Diff(map[string]testerr.Want{
+		"busy":   testerr.Is(errBusy),
+		"eof":    testerr.Is(io.ErrUnexpectedEOF),
+		"panics": testerr.Is(errBusy),
+		"never":  testerr.Is(io.EOF),
+		// "ok" is implicitly expected to be nil
+	}))
+
+	// Output:
+	// 3 of 5 goroutine(s) failed:
+	// 	[eof] got error EOF; want error that Is() unexpected EOF
+	// 	[never] no goroutine; want error that Is() EOF
+	// 	[panics] got error panic: oops; want error that Is() busy
+}
+
+func TestCollectorConcurrent(t *testing.T) {
+	// This test is most meaningful with the race detector enabled.
+	const n = 100
+	errOdd := errors.New("odd")
+
+	var c testerr.Collector
+	wants := make(map[string]testerr.Want)
+	for i := range n {
+		name := fmt.Sprint(i)
+		c.Go(name, func() error {
+			if i%2 == 1 {
+				return fmt.Errorf("worker %d: %w", i, errOdd)
+			}
+			return nil
+		})
+		if i%2 == 1 {
+			wants[name] = testerr.Is(errOdd)
+		}
+	}
+
+	ch := make(chan error)
+	c.FromChan("chan", ch)
+	var senders sync.WaitGroup
+	for i := range n {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			if i%10 == 0 {
+				ch <- nil // ignored
+				return
+			}
+			ch <- fmt.Errorf("sent %d: %w", i, io.EOF)
+		}()
+	}
+	go func() {
+		senders.Wait()
+		close(ch)
+	}()
+	wants["chan"] = testerr.All(testerr.Is(io.EOF), testerr.JoinedLen(n-n/10))
+
+	if diff := c.Diff(wants); diff != "" {
+		t.Errorf("Collector.Diff(…) %s", diff)
+	}
+}
+
+func TestCollector(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*testerr.Collector)
+		wants    map[string]testerr.Want
+		wantDiff bool
+	}{
+		{
+			name: "nothing collected",
+		},
+		{
+			name:  "missing Want is nil",
+			setup: func(c *testerr.Collector) { c.Go("w", func() error { return io.EOF }) },
+			wants: nil,
+			// i.e. want nil
+			wantDiff: true,
+		},
+		{
+			name:  "panic with error value",
+			setup: func(c *testerr.Collector) { c.Go("w", func() error { panic(io.EOF) }) },
+			wants: map[string]testerr.Want{"w": testerr.All(testerr.Is(io.EOF), testerr.MessageIs("panic: EOF"))},
+		},
+		{
+			name:  "panic with non-error value",
+			setup: func(c *testerr.Collector) { c.Go("w", func() error { panic(42) }) },
+			wants: map[string]testerr.Want{"w": testerr.As(func(p testerr.PanicValue) string {
+				if p.Value != 42 || !strings.Contains(p.Stack, "collector_test.go") {
+					return "Value 42 and Stack including collector_test.go"
+				}
+				return ""
+			})},
+		},
+		{
+			name:  "panic always fails nil Want",
+			setup: func(c *testerr.Collector) { c.Go("w", func() error { panic(nil) }) },
+			// i.e. want nil
+			wantDiff: true,
+		},
+		{
+			name: "runtime.Goexit",
+			setup: func(c *testerr.Collector) {
+				c.Go("w", func() error {
+					runtime.Goexit()
+					return nil
+				})
+			},
+			wants: map[string]testerr.Want{"w": testerr.MessageIs("runtime.Goexit() called")},
+		},
+		{
+			name: "runtime.Goexit always fails nil Want",
+			setup: func(c *testerr.Collector) {
+				c.Go("w", func() error {
+					runtime.Goexit()
+					return nil
+				})
+			},
+			// i.e. want nil
+			wantDiff: true,
+		},
+		{
+			name: "closed channel without errors",
+			setup: func(c *testerr.Collector) {
+				ch := make(chan error)
+				close(ch)
+				c.FromChan("ch", ch)
+			},
+			wants: map[string]testerr.Want{"ch": nil},
+		},
+		{
+			name: "same name joined",
+			setup: func(c *testerr.Collector) {
+				c.Go("w", func() error { return io.EOF })
+				c.Go("w", func() error { return io.ErrUnexpectedEOF })
+			},
+			wants: map[string]testerr.Want{"w": testerr.JoinedUnordered(testerr.Is(io.EOF), testerr.Is(io.ErrUnexpectedEOF))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c testerr.Collector
+			if tt.setup != nil {
+				tt.setup(&c)
+			}
+			diff := c.Diff(tt.wants)
+			if got := diff != ""; got != tt.wantDiff {
+				t.Errorf("Collector.Diff(…) got diff %q; want non-empty = %t", diff, tt.wantDiff)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package testerr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// All checks that the `got` error results in an empty diff from every one of
+// the `wants`, which are each treated in the same manner as by [Diff] so a nil
+// element requires a nil error. The diff reports the index of every [Want]
+// that failed, alongside its own diff.
+//
+// All() without any arguments is rejected, always returning a diff, as it is
+// more likely to be a mistake than an intentional lack of expectations.
+func All(wants ...Want) Want {
+	return &described{
+		desc: fmt.Sprintf("all of %s", describeAll(wants)),
+		match: func(got error) bool {
+			for _, w := range wants {
+				if !Matches(got, w) {
+					return false
+				}
+			}
+			return len(wants) > 0
+		},
+		diff: func(got error) string {
+			if len(wants) == 0 {
+				return DiffMessage(got, "All() of at least one expectation")
+			}
+
+			var failed []string
+			for i, w := range wants {
+				if d := Diff(got, w); d != "" {
+					failed = append(failed, indexedDiff(i, d))
+				}
+			}
+			if len(failed) == 0 {
+				return ""
+			}
+			return DiffMessage(
+				got, "all of %d expectations; %d failed:\n%s",
+				len(wants), len(failed), strings.Join(failed, "\n"),
+			)
+		},
+	}
+}
+
+// indexedDiff returns the diff, indented and prefixed with its index.
+func indexedDiff(i int, diff string) string {
+	return fmt.Sprintf("\t[%d] %s", i, strings.ReplaceAll(diff, "\n", "\n\t"))
+}
+
+// describeAll returns the [Describe] values of all `wants` as a bracketed,
+// semicolon-separated list.
+func describeAll(wants []Want) string {
+	descs := make([]string, len(wants))
+	for i, w := range wants {
+		descs[i] = Describe(w)
+	}
+	return fmt.Sprintf("[%s]", strings.Join(descs, "; "))
+}
+
+// Any checks that the `got` error results in an empty diff from at least one
+// of the `wants`, which are each treated in the same manner as by [Diff] so a
+// nil element accepts a nil error. If none match, the diff reports every
+// alternative's diff, prefixed by its index.
+//
+// Any() without any arguments can never be satisfied and always returns a
+// diff.
+func Any(wants ...Want) Want {
+	return &described{
+		desc:  fmt.Sprintf("any of %s", describeAll(wants)),
+		match: func(got error) bool { return anyMatches(got, wants) },
+		diff: func(got error) string {
+			if len(wants) == 0 {
+				return DiffMessage(got, "Any() of at least one expectation")
+			}
+			// Diffs of earlier alternatives are only worth constructing if
+			// none match.
+			if anyMatches(got, wants) {
+				return ""
+			}
+
+			var failed []string
+			for i, w := range wants {
+				failed = append(failed, indexedDiff(i, Diff(got, w)))
+			}
+			return DiffMessage(
+				got, "any of %d expectations; all failed:\n%s",
+				len(wants), strings.Join(failed, "\n"),
+			)
+		},
+	}
+}
+
+func anyMatches(got error, wants []Want) bool {
+	for _, w := range wants {
+		if Matches(got, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// Not inverts `want`, checking that the `got` error results in a non-empty
+// diff from it. As a nil [Want] corresponds to a nil error, `Not(nil)` checks
+// for any non-nil error.
+func Not(want Want) Want {
+	if isNil(want) {
+		return predicate("non-nil error", func(got error) bool { return got != nil })
+	}
+	desc := fmt.Sprintf("NOT (%s)", Describe(want))
+	return predicate(desc, func(got error) bool { return !Matches(got, want) })
+}
+
+// Named delegates to `want`, treated in the same manner as by [Diff], but
+// replaces the expectation reported on mismatch with `desc`. This allows
+// complex [Want]s, such as those returned by [As], to produce stable,
+// self-explanatory diffs. The returned [Want] is also described by `desc`.
+func Named(desc string, want Want) Want {
+	return predicate(desc, func(got error) bool { return Matches(got, want) })
+}
+
+// Map checks the error derived from the `got` error by `extract`, against
+// `want`, which is treated in the same manner as by [Diff]. This is useful for
+// errors that expose another error via a method other than `Unwrap()`, e.g. a
+// legacy `Cause()`. In addition to the derived error, `extract` MUST return a
+// short description of the derivation (e.g. "Cause()"), which is used in the
+// diff. It is called for every `got` error, including nil.
+//
+// If `extract` returns a nil error then `want` is checked against nil, so a
+// nil [Want] expects the derivation to yield nothing. A nil `extract` always
+// results in a diff.
+func Map(extract func(error) (error, string), want Want) Want {
+	desc := fmt.Sprintf("derived error matching %s", Describe(want))
+	if extract == nil {
+		return &described{
+			desc: desc,
+			diff: func(got error) string {
+				return DiffMessage(got, "%s but Map() extract function is nil", desc)
+			},
+		}
+	}
+	return &described{
+		desc: desc,
+		diff: func(got error) string {
+			derived, what := extract(got)
+			if Matches(derived, want) {
+				return ""
+			}
+			d := Diff(derived, want)
+			if derived == nil {
+				return DiffMessage(got, "%s to yield %s; got nil:\n\t%s", what, Describe(want), d)
+			}
+			return DiffMessage(got, "%s to yield %s:\n\t%s", what, Describe(want), d)
+		},
+	}
+}
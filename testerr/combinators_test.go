@@ -0,0 +1,251 @@
+package testerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+func ExampleAll() {
+	errQuota := errors.New("quota exceeded")
+	err := fmt.Errorf("bucket %q: %w", "b1", errQuota)
+
+	tests := []struct {
+		name string
+		want testerr.Want
+	}{
+		{
+			name: "all matched",
+			want: testerr.All(testerr.Is(errQuota), testerr.Contains(`"b1"`)),
+		},
+		{
+			name: "some failed",
+			want: testerr.All(
+				testerr.Is(errQuota),
+				testerr.Contains(`"b2"`),
+				nil, // i.e. want nil error
+			),
+		},
+		{
+			name: "no arguments",
+			want: testerr.All(),
+		},
+	}
+
+	for _, tt := range tests {
+		fmt.Println("---", tt.name, "---")
+		if diff := testerr.Diff(err, tt.want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// --- all matched ---
+	// <empty>
+	// --- some failed ---
+	// got error bucket "b1": quota exceeded; want all of 3 expectations; 2 failed:
+	// 	[1] got error bucket "b1": quota exceeded; want containing substring "\"b2\""
+	// 	[2] got error bucket "b1": quota exceeded; want nil
+	// --- no arguments ---
+	// got error bucket "b1": quota exceeded; want All() of at least one expectation
+}
+
+func ExampleAny() {
+	errReset := errors.New("connection reset")
+	anyOf := testerr.Any(
+		testerr.Is(context.Canceled),
+		testerr.Is(errReset),
+	)
+
+	tests := []struct {
+		name string
+		err  error
+		want testerr.Want
+	}{
+		{
+			name: "first alternative",
+			err:  fmt.Errorf("dial: %w", context.Canceled),
+			want: anyOf,
+		},
+		{
+			name: "second alternative only",
+			err:  fmt.Errorf("read: %w", errReset),
+			want: anyOf,
+		},
+		{
+			name: "none matched",
+			err:  io.EOF,
+			want: anyOf,
+		},
+		{
+			name: "nil alternative accepts nil error",
+			err:  nil,
+			want: testerr.Any(testerr.Is(errReset), nil),
+		},
+		{
+			name: "no arguments",
+			err:  nil,
+			want: testerr.Any(),
+		},
+	}
+
+	for _, tt := range tests {
+		fmt.Println("---", tt.name, "---")
+		if diff := testerr.Diff(tt.err, tt.want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// --- first alternative ---
+	// <empty>
+	// --- second alternative only ---
+	// <empty>
+	// --- none matched ---
+	// got error EOF; want any of 2 expectations; all failed:
+	// 	[0] got error EOF; want error that Is() context canceled
+	// 	[1] got error EOF; want error that Is() connection reset
+	// --- nil alternative accepts nil error ---
+	// <empty>
+	// --- no arguments ---
+	// got error <nil>; want Any() of at least one expectation
+}
+
+func ExampleNot() {
+	errRetry := fmt.Errorf("giving up: %w", io.ErrUnexpectedEOF)
+	errDeadline := fmt.Errorf("giving up: %w", context.DeadlineExceeded)
+
+	tests := []struct {
+		name string
+		err  error
+		want testerr.Want
+	}{
+		{
+			name: "inner Want not matched",
+			err:  errRetry,
+			want: testerr.Not(testerr.Is(context.DeadlineExceeded)),
+		},
+		{
+			name: "inner Want matched",
+			err:  errDeadline,
+			want: testerr.Not(testerr.Is(context.DeadlineExceeded)),
+		},
+		{
+			name: "Not(nil) with non-nil error",
+			err:  errRetry,
+			want: testerr.Not(nil),
+		},
+		{
+			name: "Not(nil) with nil error",
+			err:  nil,
+			want: testerr.Not(nil),
+		},
+		{
+			name: "inner Func without a description",
+			err:  errRetry,
+			want: testerr.Not(testerr.Func(func(error) string { return "" })),
+		},
+	}
+
+	for _, tt := range tests {
+		fmt.Println("---", tt.name, "---")
+		if diff := testerr.Diff(tt.err, tt.want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// --- inner Want not matched ---
+	// <empty>
+	// --- inner Want matched ---
+	// got error giving up: context deadline exceeded; want NOT (error that Is() context deadline exceeded)
+	// --- Not(nil) with non-nil error ---
+	// <empty>
+	// --- Not(nil) with nil error ---
+	// got error <nil>; want non-nil error
+	// --- inner Func without a description ---
+	// got error giving up: unexpected EOF; want NOT (expectation of type testerr.Func)
+}
+
+func ExampleNamed() {
+	quota := testerr.Named(
+		"quota exceeded for bucket b1",
+		testerr.As(func(got myError) string {
+			if got.val != 1 {
+				return "bucket 1"
+			}
+			return ""
+		}),
+	)
+
+	for _, err := range []error{myError{1}, myError{2}, nil} {
+		if diff := testerr.Diff(err, quota); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	fmt.Println(testerr.Diff(io.EOF, testerr.Named("no error at all", nil)))
+
+	// Output:
+	// <empty>
+	// got error val 2 is not good; want quota exceeded for bucket b1
+	// got error <nil>; want quota exceeded for bucket b1
+	// got error EOF; want no error at all
+}
+
+// legacyError exposes its cause via a method that isn't part of the unwrap
+// chain.
+type legacyError struct {
+	msg   string
+	cause error
+}
+
+func (e *legacyError) Error() string { return e.msg }
+func (e *legacyError) Cause() error  { return e.cause }
+
+func ExampleMap() {
+	cause := func(err error) (error, string) {
+		var l *legacyError
+		if !errors.As(err, &l) {
+			return nil, "*legacyError.Cause()"
+		}
+		return l.Cause(), "*legacyError.Cause()"
+	}
+
+	for _, tt := range []struct {
+		err  error
+		want testerr.Want
+	}{
+		{&legacyError{"failed", io.EOF}, testerr.Map(cause, testerr.Is(io.EOF))},
+		{&legacyError{"failed", io.ErrUnexpectedEOF}, testerr.Map(cause, testerr.Is(io.EOF))},
+		{&legacyError{"failed", nil}, testerr.Map(cause, nil)},
+		{errors.New("not legacy"), testerr.Map(cause, testerr.Is(io.EOF))},
+		{io.EOF, testerr.Map(nil, testerr.Is(io.EOF))},
+	} {
+		if diff := testerr.Diff(tt.err, tt.want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// got error failed; want *legacyError.Cause() to yield error that Is() EOF:
+	// 	got error unexpected EOF; want error that Is() EOF
+	// <empty>
+	// got error not legacy; want *legacyError.Cause() to yield error that Is() EOF; got nil:
+	// 	got error <nil>; want error that Is() EOF
+	// got error EOF; want derived error matching error that Is() EOF but Map() extract function is nil
+}
@@ -0,0 +1,86 @@
+package testerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Canceled checks that the `got` error [errors.Is] [context.Canceled] and that
+// it also results in an empty diff from `cause`, which is treated in the same
+// manner as by [Diff]. It is intended for errors that wrap both `ctx.Err()`
+// and [context.Cause], the former of which is commonly omitted; the diff notes
+// when only the cause is present. As the cause is checked against the same
+// error, a nil `cause` never matches and [Is] SHOULD be used to ignore it.
+//
+// A context canceled without an explicit cause has [context.Canceled] as its
+// cause, which is therefore matched by `Canceled(Is(context.Canceled))`.
+func Canceled(cause Want) Want {
+	return ctxErr(context.Canceled, "context.Canceled", cause)
+}
+
+// DeadlineExceeded is equivalent to [Canceled] but for
+// [context.DeadlineExceeded], as it is returned by contexts with deadlines or
+// timeouts, including those created with a cause.
+func DeadlineExceeded(cause Want) Want {
+	return ctxErr(context.DeadlineExceeded, "context.DeadlineExceeded", cause)
+}
+
+func ctxErr(target error, name string, cause Want) Want {
+	desc := fmt.Sprintf("error that Is() %s with cause %s", name, Describe(cause))
+	return &described{
+		desc: desc,
+		match: func(got error) bool {
+			return got != nil && errors.Is(got, target) && Matches(got, cause)
+		},
+		diff: func(got error) string {
+			if got == nil {
+				return DiffMessage(got, "%s", desc)
+			}
+			isTarget, isCause := errors.Is(got, target), Matches(got, cause)
+			switch {
+			case isTarget && isCause:
+				return ""
+			case isTarget:
+				return DiffMessage(got, "%s; cause: %s", desc, Diff(got, cause))
+			case isCause:
+				return DiffMessage(got, "%s; got matching cause but not %s, as returned by context.Cause() alone", desc, name)
+			default:
+				return DiffMessage(got, "%s; not %s and cause: %s", desc, name, Diff(got, cause))
+			}
+		},
+	}
+}
+
+// DiffCtx checks `ctx.Err()` against `errWant` and [context.Cause] of `ctx`
+// against `causeWant`, each in the same manner as by [Diff]. The diff has a
+// line for each that failed, prefixed by "Err(): " or "Cause(): " respectively.
+//
+// A context that isn't done has both a nil error and a nil cause, so it is
+// matched by nil [Want]s, and any diff notes that the context isn't done. A
+// context canceled without an explicit cause, including by a deadline, has a
+// cause equal to its error; a failing `causeWant` diff notes as much.
+func DiffCtx(ctx context.Context, errWant, causeWant Want) string {
+	err, cause := ctx.Err(), context.Cause(ctx)
+
+	var note string
+	switch {
+	case err == nil:
+		note = " (context isn't done)"
+	case cause == err:
+		note = " (no explicit cause, so Cause() is Err())"
+	}
+
+	var parts []string
+	if d := Diff(err, errWant); d != "" {
+		if err == nil {
+			d += note
+		}
+		parts = append(parts, "Err(): "+d)
+	}
+	if d := Diff(cause, causeWant); d != "" {
+		parts = append(parts, "Cause(): "+d+note)
+	}
+	return strings.Join(parts, "\n")
+}
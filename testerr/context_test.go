@@ -0,0 +1,181 @@
+package testerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+var errShutdown = errors.New("shutdown")
+
+func ExampleDiffCtx() {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errShutdown)
+
+	fmt.Println(testerr.DiffCtx(ctx, testerr.Is(context.Canceled), testerr.Is(errShutdown)))
+	fmt.Println(testerr.DiffCtx(ctx, testerr.Is(context.DeadlineExceeded), testerr.Is(context.Canceled)))
+
+	// Output:
+	// Err(): got error context canceled; want error that Is() context deadline exceeded
+	// Cause(): got error shutdown; want error that Is() context canceled
+}
+
+func TestDiffCtx(t *testing.T) {
+	notDone, cancelNotDone := context.WithCancel(context.Background())
+	defer cancelNotDone()
+
+	withCause, cancelWithCause := context.WithCancelCause(context.Background())
+	cancelWithCause(errShutdown)
+
+	withoutCause, cancelWithoutCause := context.WithCancelCause(context.Background())
+	cancelWithoutCause(nil)
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Unix(0, 0))
+	defer cancelExpired()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		errWant   testerr.Want
+		causeWant testerr.Want
+		wantDiff  string // empty if no diff is expected
+	}{
+		{
+			name: "not done with nil Wants",
+			ctx:  notDone,
+		},
+		{
+			name:      "not done with non-nil Wants",
+			ctx:       notDone,
+			errWant:   testerr.Is(context.Canceled),
+			causeWant: testerr.Is(errShutdown),
+			wantDiff:  "Err(): got error <nil>; want error that Is() context canceled (context isn't done)\nCause(): got error <nil>; want error that Is() shutdown (context isn't done)",
+		},
+		{
+			name:      "explicit cause",
+			ctx:       withCause,
+			errWant:   testerr.Is(context.Canceled),
+			causeWant: testerr.Is(errShutdown),
+		},
+		{
+			name:      "only Err() checked correctly",
+			ctx:       withCause,
+			errWant:   testerr.Is(context.Canceled),
+			causeWant: testerr.Is(context.Canceled),
+			wantDiff:  "Cause(): got error shutdown; want error that Is() context canceled",
+		},
+		{
+			name:      "only Cause() checked correctly",
+			ctx:       withCause,
+			errWant:   testerr.Is(errShutdown),
+			causeWant: testerr.Is(errShutdown),
+			wantDiff:  "Err(): got error context canceled; want error that Is() shutdown",
+		},
+		{
+			name:      "no explicit cause is Err()",
+			ctx:       withoutCause,
+			errWant:   testerr.Is(context.Canceled),
+			causeWant: testerr.Is(context.Canceled),
+		},
+		{
+			name:      "no explicit cause with cause Want",
+			ctx:       withoutCause,
+			errWant:   testerr.Is(context.Canceled),
+			causeWant: testerr.Is(errShutdown),
+			wantDiff:  "Cause(): got error context canceled; want error that Is() shutdown (no explicit cause, so Cause() is Err())",
+		},
+		{
+			name:      "deadline without explicit cause",
+			ctx:       expired,
+			errWant:   testerr.Is(context.DeadlineExceeded),
+			causeWant: testerr.Is(context.DeadlineExceeded),
+		},
+		{
+			name:     "done with nil Wants",
+			ctx:      withCause,
+			wantDiff: "Err(): got error context canceled; want nil\nCause(): got error shutdown; want nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := testerr.DiffCtx(tt.ctx, tt.errWant, tt.causeWant); diff != tt.wantDiff {
+				t.Errorf("DiffCtx(…) got %q; want %q", diff, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func TestCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errShutdown)
+	both := fmt.Errorf("%w: %w", ctx.Err(), context.Cause(ctx))
+
+	tests := []struct {
+		name     string
+		err      error
+		want     testerr.Want
+		wantDiff string // following "want"; empty if no diff is expected
+	}{
+		{
+			name: "Err() and Cause() wrapped",
+			err:  both,
+			want: testerr.Canceled(testerr.Is(errShutdown)),
+		},
+		{
+			name: "no explicit cause",
+			err:  context.Canceled,
+			want: testerr.Canceled(testerr.Is(context.Canceled)),
+		},
+		{
+			name:     "only Cause()",
+			err:      context.Cause(ctx),
+			want:     testerr.Canceled(testerr.Is(errShutdown)),
+			wantDiff: "error that Is() context.Canceled with cause error that Is() shutdown; got matching cause but not context.Canceled, as returned by context.Cause() alone",
+		},
+		{
+			name:     "only Err()",
+			err:      ctx.Err(),
+			want:     testerr.Canceled(testerr.Is(errShutdown)),
+			wantDiff: "error that Is() context.Canceled with cause error that Is() shutdown; cause: got error context canceled; want error that Is() shutdown",
+		},
+		{
+			name:     "neither",
+			err:      errors.New("other"),
+			want:     testerr.Canceled(testerr.Is(errShutdown)),
+			wantDiff: "error that Is() context.Canceled with cause error that Is() shutdown; not context.Canceled and cause: got error other; want error that Is() shutdown",
+		},
+		{
+			name:     "nil error",
+			want:     testerr.Canceled(testerr.Is(errShutdown)),
+			wantDiff: "error that Is() context.Canceled with cause error that Is() shutdown",
+		},
+		{
+			name:     "nil cause Want",
+			err:      both,
+			want:     testerr.Canceled(nil),
+			wantDiff: "error that Is() context.Canceled with cause nil; cause: got error context canceled: shutdown; want nil",
+		},
+		{
+			name:     "DeadlineExceeded() of cancellation",
+			err:      both,
+			want:     testerr.DeadlineExceeded(testerr.Is(errShutdown)),
+			wantDiff: "error that Is() context.DeadlineExceeded with cause error that Is() shutdown; got matching cause but not context.DeadlineExceeded, as returned by context.Cause() alone",
+		},
+		{
+			name: "DeadlineExceeded() with cause",
+			err:  fmt.Errorf("%w: %w", context.DeadlineExceeded, errShutdown),
+			want: testerr.DeadlineExceeded(testerr.Is(errShutdown)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkDiff(t, tt.err, tt.want, tt.wantDiff)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package testerr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffAll compares each of the `got` errors with the [Want] at the same index,
+// in the same manner as by [Diff], so a nil element of `want` expects a nil
+// error. This is intended for bulk operations that return one error per input,
+// with nil denoting success. The diff has one line per failing index, prefixed
+// by said index; indices that match aren't reported. If the lengths differ, the
+// diff says so and the indices common to both are still compared.
+func DiffAll(got []error, want []Want) string {
+	var lengths string
+	if len(got) != len(want) {
+		lengths = fmt.Sprintf("got %d error(s); want %d", len(got), len(want))
+	}
+	n := min(len(got), len(want))
+	return aggregateDiff(lengths, n, func(i int) string { return Diff(got[i], want[i]) })
+}
+
+// Each returns a function that compares every one of its errors with `want`, in
+// the same manner as by [Diff], reporting failing indices as [DiffAll] does.
+// For example, `Each(nil)` checks that all errors are nil. An empty slice of
+// errors always results in an empty diff.
+func Each(want Want) func([]error) string {
+	return func(got []error) string {
+		return aggregateDiff("", len(got), func(i int) string { return Diff(got[i], want) })
+	}
+}
+
+// aggregateDiff returns the `header`, if non-empty, followed by the
+// [indexedDiff] of every index in [0,n) for which `diff` is non-empty.
+func aggregateDiff(header string, n int, diff func(int) string) string {
+	var failed []string
+	for i := range n {
+		if d := diff(i); d != "" {
+			failed = append(failed, indexedDiff(i, d))
+		}
+	}
+
+	var parts []string
+	if header != "" {
+		parts = append(parts, header)
+	}
+	if len(failed) > 0 {
+		parts = append(parts, fmt.Sprintf(
+			"%d of %d compared error(s) mismatched:\n%s",
+			len(failed), n, strings.Join(failed, "\n"),
+		))
+	}
+	return strings.Join(parts, "\n")
+}
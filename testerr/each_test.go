@@ -0,0 +1,114 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+func ExampleDiffAll() {
+	errSkipped := errors.New("skipped")
+	got := []error{nil, fmt.Errorf("item 1: %w", errSkipped), io.EOF, nil}
+
+	fmt.Println(testerr.DiffAll(got, []testerr.Want{
+		nil,
+		testerr.Is(errSkipped),
+		nil,
+		testerr.Is(errSkipped),
+	}))
+	fmt.Println("---")
+	fmt.Println(testerr.Each(testerr.Not(testerr.Is(io.EOF)))(got))
+
+	// Output:
+	// 2 of 4 compared error(s) mismatched:
+	// 	[2] got error EOF; want nil
+	// 	[3] got error <nil>; want error that Is() skipped
+	// ---
+	// 1 of 4 compared error(s) mismatched:
+	// 	[2] got error EOF; want NOT (error that Is() EOF)
+}
+
+func TestDiffAll(t *testing.T) {
+	errA := errors.New("a")
+
+	tests := []struct {
+		name     string
+		got      []error
+		want     []testerr.Want
+		wantDiff string
+	}{
+		{
+			name: "both empty",
+		},
+		{
+			name: "all match",
+			got:  []error{nil, errA},
+			want: []testerr.Want{testerr.Nil(), testerr.Is(errA)},
+		},
+		{
+			name:     "more errors than Wants",
+			got:      []error{nil, errA, io.EOF},
+			want:     []testerr.Want{nil, nil},
+			wantDiff: "got 3 error(s); want 2\n1 of 2 compared error(s) mismatched:\n\t[1] got error a; want nil",
+		},
+		{
+			name:     "fewer errors than Wants with common indices matching",
+			got:      []error{errA},
+			want:     []testerr.Want{testerr.Is(errA), nil},
+			wantDiff: "got 1 error(s); want 2",
+		},
+		{
+			name:     "multi-line diffs are indented",
+			got:      []error{errA},
+			want:     []testerr.Want{testerr.All(testerr.Is(io.EOF))},
+			wantDiff: "1 of 1 compared error(s) mismatched:\n\t[0] got error a; want all of 1 expectations; 1 failed:\n\t\t[0] got error a; want error that Is() EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testerr.DiffAll(tt.got, tt.want); got != tt.wantDiff {
+				t.Errorf("DiffAll(%v, …) got:\n%s\nwant:\n%s", tt.got, got, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func TestEach(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      []error
+		want     testerr.Want
+		wantDiff string
+	}{
+		{
+			name: "empty",
+			want: testerr.Is(io.EOF),
+		},
+		{
+			name: "all nil",
+			got:  []error{nil, nil},
+		},
+		{
+			name:     "nil Want with some errors",
+			got:      []error{nil, io.EOF, nil, io.ErrUnexpectedEOF},
+			wantDiff: "2 of 4 compared error(s) mismatched:\n\t[1] got error EOF; want nil\n\t[3] got error unexpected EOF; want nil",
+		},
+		{
+			name: "all Is()",
+			got:  []error{io.EOF, fmt.Errorf("x: %w", io.EOF)},
+			want: testerr.Is(io.EOF),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testerr.Each(tt.want)(tt.got); got != tt.wantDiff {
+				t.Errorf("Each(%s)(%v) got:\n%s\nwant:\n%s", testerr.Describe(tt.want), tt.got, got, tt.wantDiff)
+			}
+		})
+	}
+}
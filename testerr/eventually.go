@@ -0,0 +1,48 @@
+package testerr
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Eventually is equivalent to [EventuallyDiff] except that a non-empty diff is
+// reported via `t.Fatalf()`, stopping the test.
+func Eventually(t testing.TB, fn func() error, want Want, timeout, interval time.Duration) {
+	t.Helper()
+	if diff := EventuallyDiff(t, fn, want, timeout, interval); diff != "" {
+		t.Fatalf("Eventually() %s", diff)
+	}
+}
+
+// EventuallyDiff repeatedly calls `fn`, every `interval`, until [Diff] of its
+// returned error and `want` is empty, in which case it returns an empty
+// string. If `timeout` elapses first, or the test's deadline (see
+// [testing.T.Deadline]) is reached if sooner, it returns the last diff along
+// with the number of attempts and the duration over which they were made.
+//
+// The wait before the final attempt is shortened so as not to sleep past the
+// deadline. `fn` is always called at least once, and SHOULD return promptly as
+// calls aren't interrupted.
+func EventuallyDiff(t testing.TB, fn func() error, want Want, timeout, interval time.Duration) string {
+	t.Helper()
+	start := time.Now()
+	deadline := start.Add(timeout)
+	if dt, ok := t.(interface{ Deadline() (time.Time, bool) }); ok {
+		if d, ok := dt.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+	}
+
+	for attempts := 1; ; attempts++ {
+		diff := Diff(fn(), want)
+		if diff == "" {
+			return ""
+		}
+		now := time.Now()
+		if !now.Before(deadline) {
+			return fmt.Sprintf("after %d attempt(s) over %v: %s", attempts, now.Sub(start).Round(time.Millisecond), diff)
+		}
+		time.Sleep(min(interval, deadline.Sub(now)))
+	}
+}
@@ -0,0 +1,117 @@
+package testerr_test
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// succeedAfter returns a function that returns `errNotReady` for the first `n`
+// calls and nil thereafter, along with a pointer to the number of calls.
+func succeedAfter(n int, errNotReady error) (func() error, *int) {
+	var calls int
+	return func() error {
+		calls++
+		if calls <= n {
+			return errNotReady
+		}
+		return nil
+	}, &calls
+}
+
+func TestEventuallyDiff(t *testing.T) {
+	errNotReady := errors.New("not ready")
+
+	t.Run("eventually matches", func(t *testing.T) {
+		fn, calls := succeedAfter(3, errNotReady)
+		if diff := testerr.EventuallyDiff(t, fn, nil, time.Minute, time.Millisecond); diff != "" {
+			t.Errorf("EventuallyDiff(<nil after 3 calls>, nil, …) %s", diff)
+		}
+		if *calls != 4 {
+			t.Errorf("EventuallyDiff(<nil after 3 calls>, nil, …) called function %d times; want 4", *calls)
+		}
+	})
+
+	t.Run("immediate match", func(t *testing.T) {
+		fn, calls := succeedAfter(0, errNotReady)
+		if diff := testerr.EventuallyDiff(t, fn, nil, 0, time.Hour); diff != "" {
+			t.Errorf("EventuallyDiff(<nil>, nil, 0, …) %s", diff)
+		}
+		if *calls != 1 {
+			t.Errorf("EventuallyDiff(<nil>, nil, 0, …) called function %d times; want 1", *calls)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		fn, calls := succeedAfter(1e6, errNotReady)
+		start := time.Now()
+		diff := testerr.EventuallyDiff(t, fn, nil, 50*time.Millisecond, time.Hour)
+
+		re := regexp.MustCompile(`^after (\d+) attempt\(s\) over \d+ms: got error not ready; want nil$`)
+		if !re.MatchString(diff) {
+			t.Errorf("EventuallyDiff(<never nil>, nil, …) got %q; want matching %q", diff, re)
+		}
+		if *calls != 2 || !strings.HasPrefix(diff, "after "+strconv.Itoa(*calls)+" ") {
+			t.Errorf("EventuallyDiff(<never nil>, nil, …) called function %d times and reported %q", *calls, diff)
+		}
+		// The sleep is shortened to the deadline, not the full interval, and
+		// followed by a final attempt.
+		if elapsed := time.Since(start); elapsed > 10*time.Second {
+			t.Errorf("EventuallyDiff(…, timeout = 50ms, interval = 1h) took %v", elapsed)
+		}
+	})
+
+	t.Run("test deadline sooner than timeout", func(t *testing.T) {
+		fn, _ := succeedAfter(1e6, errNotReady)
+		tb := &deadlineTB{TB: t, deadline: time.Now().Add(20 * time.Millisecond)}
+
+		start := time.Now()
+		if diff := testerr.EventuallyDiff(tb, fn, nil, time.Hour, time.Hour); diff == "" {
+			t.Error("EventuallyDiff(<never nil>, nil, …) got empty diff")
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("EventuallyDiff(…) with test deadline in 20ms took %v", elapsed)
+		}
+	})
+}
+
+// deadlineTB overrides the deadline of the embedded [testing.TB].
+type deadlineTB struct {
+	testing.TB
+	deadline time.Time
+}
+
+func (d *deadlineTB) Deadline() (time.Time, bool) { return d.deadline, true }
+
+func TestEventually(t *testing.T) {
+	errNotReady := errors.New("not ready")
+
+	t.Run("match", func(t *testing.T) {
+		fn, _ := succeedAfter(2, errNotReady)
+		fake := new(fakeTB)
+		if !fake.run(func(tb testing.TB) { testerr.Eventually(tb, fn, nil, time.Minute, time.Millisecond) }) {
+			t.Errorf("Eventually(<nil after 2 calls>, nil, …) stopped the test with %q", fake.fatals)
+		}
+		if !fake.helper {
+			t.Error("Eventually() didn't call t.Helper()")
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		fn, _ := succeedAfter(1e6, errNotReady)
+		fake := new(fakeTB)
+		if fake.run(func(tb testing.TB) { testerr.Eventually(tb, fn, nil, 0, time.Millisecond) }) {
+			t.Error("Eventually(<never nil>, nil, …) didn't stop the test")
+		}
+		want := []string{"Eventually() after 1 attempt(s) over 0s: got error not ready; want nil"}
+		if !slices.Equal(fake.fatals, want) {
+			t.Errorf("Eventually(<never nil>, nil, …) reported fatals %q; want %q", fake.fatals, want)
+		}
+	})
+}
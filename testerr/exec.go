@@ -0,0 +1,83 @@
+package testerr
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// ExitCode checks that the `got` error unwraps, via [errors.As], to an
+// [exec.ExitError] with the exit code. A process terminated by a signal has an
+// exit code of -1, which is reported as such rather than as a number.
+func ExitCode(code int) Want {
+	desc := fmt.Sprintf("*exec.ExitError with exit code %d", code)
+	return &described{
+		desc:    desc,
+		details: treeTypesDetails,
+		match: func(got error) bool {
+			e, ok := as[*exec.ExitError](got)
+			return ok && e.ExitCode() == code
+		},
+		diff: func(got error) string {
+			e, ok := as[*exec.ExitError](got)
+			if !ok {
+				return typeDiff(got, desc)
+			}
+			if d := exitCodeDiff(e, code); d != "" {
+				return DiffMessage(got, "%s; %s", desc, d)
+			}
+			return ""
+		},
+	}
+}
+
+// exitCodeDiff returns a description of how the exit code of `e` differs from
+// `code`, or the empty string if it doesn't.
+func exitCodeDiff(e *exec.ExitError, code int) string {
+	switch got := e.ExitCode(); {
+	case got == code:
+		return ""
+	case got == -1:
+		// ProcessState.String() describes the signal, e.g. "signal: killed".
+		return fmt.Sprintf("got process that didn't exit normally (%v)", e)
+	default:
+		return fmt.Sprintf("got exit code %d", got)
+	}
+}
+
+// Exit is equivalent to [ExitCode] but additionally checks the `Stderr` field
+// of the [exec.ExitError] against `stderr`. The captured output is checked, with
+// [Diff], as an error of which it is the string, so a nil `stderr` never
+// matches and [ExitCode] SHOULD be used instead.
+//
+// Stderr is only captured by [exec.Cmd.Output], and only if `Cmd.Stderr` is
+// nil, so the diff notes when it is empty.
+func Exit(code int, stderr Want) Want {
+	desc := fmt.Sprintf("*exec.ExitError with exit code %d and stderr %s", code, Describe(stderr))
+	return &described{
+		desc:    desc,
+		details: treeTypesDetails,
+		match: func(got error) bool {
+			e, ok := as[*exec.ExitError](got)
+			return ok && e.ExitCode() == code && Matches(errors.New(string(e.Stderr)), stderr)
+		},
+		diff: func(got error) string {
+			e, ok := as[*exec.ExitError](got)
+			if !ok {
+				return typeDiff(got, desc)
+			}
+			if d := exitCodeDiff(e, code); d != "" {
+				return DiffMessage(got, "%s; %s", desc, d)
+			}
+			d := Diff(errors.New(string(e.Stderr)), stderr)
+			switch {
+			case d == "":
+				return ""
+			case len(e.Stderr) == 0:
+				return DiffMessage(got, "%s; got empty Stderr, which is only captured by exec.Cmd.Output() with a nil Cmd.Stderr", desc)
+			default:
+				return DiffMessage(got, "%s; Stderr: %s", desc, d)
+			}
+		},
+	}
+}
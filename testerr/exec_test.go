@@ -0,0 +1,161 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// helperProcessEnv is the environment variable that signals to
+// [TestHelperProcess] that it is running as a child process.
+const helperProcessEnv = "TESTERR_WANT_HELPER_PROCESS"
+
+// TestHelperProcess isn't a real test. When run as a child process by
+// [helperCommand], it writes its first argument to stderr and exits with the
+// code given by the second, or sleeps indefinitely if the code is "sleep".
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv(helperProcessEnv) != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	stderr, code := args[1], args[2]
+
+	fmt.Fprint(os.Stderr, stderr)
+	if code == "sleep" {
+		time.Sleep(time.Hour)
+	}
+	c, err := strconv.Atoi(code)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bad exit code: %v", err)
+		os.Exit(100)
+	}
+	os.Exit(c)
+}
+
+func helperCommand(t *testing.T, stderr, code string) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$", "--", stderr, code)
+	cmd.Env = append(os.Environ(), helperProcessEnv+"=1")
+	return cmd
+}
+
+func TestExit(t *testing.T) {
+	_, usage := helperCommand(t, "flag provided but not defined: -x", "2").Output()
+	// Run() doesn't capture stderr.
+	uncaptured := helperCommand(t, "flag provided but not defined: -x", "2").Run()
+	_, success := helperCommand(t, "", "0").Output()
+	_, notFound := exec.Command("testerr-definitely-not-a-real-binary").Output()
+
+	if success != nil {
+		t.Fatalf("helper process exiting with code 0: %v", success)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		want     testerr.Want
+		wantDiff string // following "want"; empty if no diff is expected
+	}{
+		{
+			name: "ExitCode() match",
+			err:  usage,
+			want: testerr.ExitCode(2),
+		},
+		{
+			name: "ExitCode() of wrapped error",
+			err:  fmt.Errorf("running tool: %w", usage),
+			want: testerr.ExitCode(2),
+		},
+		{
+			name:     "ExitCode() mismatch",
+			err:      usage,
+			want:     testerr.ExitCode(1),
+			wantDiff: "*exec.ExitError with exit code 1; got exit code 2",
+		},
+		{
+			name:     "ExitCode() of nil",
+			want:     testerr.ExitCode(0),
+			wantDiff: "*exec.ExitError with exit code 0",
+		},
+		{
+			name:     "ExitCode() of exec.ErrNotFound",
+			err:      notFound,
+			want:     testerr.ExitCode(2),
+			wantDiff: "*exec.ExitError with exit code 2; found types [*exec.Error, *errors.errorString]",
+		},
+		{
+			name: "Exit() match",
+			err:  usage,
+			want: testerr.Exit(2, testerr.Contains("flag provided but not defined")),
+		},
+		{
+			name:     "Exit() code mismatch",
+			err:      usage,
+			want:     testerr.Exit(3, testerr.Contains("flag provided but not defined")),
+			wantDiff: `*exec.ExitError with exit code 3 and stderr containing substring "flag provided but not defined"; got exit code 2`,
+		},
+		{
+			name:     "Exit() stderr mismatch",
+			err:      usage,
+			want:     testerr.Exit(2, testerr.Contains("usage:")),
+			wantDiff: `*exec.ExitError with exit code 2 and stderr containing substring "usage:"; Stderr: got error flag provided but not defined: -x; want containing substring "usage:"`,
+		},
+		{
+			name:     "Exit() with uncaptured stderr",
+			err:      uncaptured,
+			want:     testerr.Exit(2, testerr.Contains("flag provided but not defined")),
+			wantDiff: `*exec.ExitError with exit code 2 and stderr containing substring "flag provided but not defined"; got empty Stderr, which is only captured by exec.Cmd.Output() with a nil Cmd.Stderr`,
+		},
+		{
+			name: "Exit() with empty stderr expectation",
+			err:  uncaptured,
+			want: testerr.Exit(2, testerr.MessageIs("")),
+		},
+		{
+			name:     "Exit() of other type",
+			err:      errors.New("x"),
+			want:     testerr.Exit(2, testerr.Contains("")),
+			wantDiff: `*exec.ExitError with exit code 2 and stderr containing substring ""; found types [*errors.errorString]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkDiff(t, tt.err, tt.want, tt.wantDiff)
+		})
+	}
+}
+
+func TestExitSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Killed processes on Windows have a regular exit code")
+	}
+
+	cmd := helperCommand(t, "", "sleep")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Process.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	err := cmd.Wait()
+
+	if diff := testerr.Diff(err, testerr.ExitCode(-1)); diff != "" {
+		t.Errorf("Diff(<killed process>, ExitCode(-1)) %s", diff)
+	}
+	diff := testerr.Diff(err, testerr.Exit(0, testerr.Contains("")))
+	if want := "got process that didn't exit normally (signal: killed)"; !strings.Contains(diff, want) {
+		t.Errorf("Diff(<killed process>, Exit(0, …)) got diff %q; want containing %q", diff, want)
+	}
+}
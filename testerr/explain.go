@@ -0,0 +1,68 @@
+package testerr
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// A Detailer is a [Want] that can provide structured details about its
+// comparison with an error, for inclusion in a [Result]. The details MUST be
+// values that can be marshalled as JSON.
+type Detailer interface {
+	Want
+	Details(got error) map[string]any
+}
+
+// A Result is a machine-readable explanation of the comparison of an error
+// with a [Want], as returned by [Explain].
+type Result struct {
+	// Matched is true i.f.f. Diff is empty.
+	Matched bool `json:"matched"`
+	// GotNil is true if the got error was nil.
+	GotNil bool `json:"got_nil,omitempty"`
+	// GotMessage is the got error's message. It is empty if the error is nil
+	// or a typed nil, as described by [DiffMessage].
+	GotMessage string `json:"got_message,omitempty"`
+	// GotType is the concrete type of the got error, in %T format.
+	GotType string `json:"got_type,omitempty"`
+	// Expectation is the [Describe] value of the [Want].
+	Expectation string `json:"expectation"`
+	// Diff is identical to the value returned by [Diff].
+	Diff string `json:"diff,omitempty"`
+	// Details are populated by [Want]s that are also [Detailer]s.
+	Details map[string]any `json:"details,omitempty"`
+}
+
+var _ json.Marshaler = Result{}
+
+// MarshalJSON implements [json.Marshaler], using the field names in the
+// struct tags of [Result].
+func (r Result) MarshalJSON() ([]byte, error) {
+	type plain Result // avoids recursion
+	return json.Marshal(plain(r))
+}
+
+// Explain compares the error with what is wanted, in the same manner as
+// [Diff], and returns a [Result] that includes the diff. If `want` is a
+// [Detailer], its details are also included.
+func Explain(got error, want Want) Result {
+	diff := Diff(got, want)
+	r := Result{
+		Matched:     diff == "",
+		GotNil:      got == nil,
+		Expectation: Describe(want),
+		Diff:        diff,
+	}
+	if got != nil {
+		r.GotType = fmt.Sprintf("%T", got)
+		if _, ok := typedNil(got); !ok {
+			// A failure description is preferable to a panic, and
+			// is also reported by the Diff.
+			r.GotMessage, _ = message(got)
+		}
+	}
+	if d, ok := want.(Detailer); ok && !isNil(want) {
+		r.Details = d.Details(got)
+	}
+	return r
+}
@@ -0,0 +1,134 @@
+package testerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+	"github.com/google/go-cmp/cmp"
+)
+
+func ExampleExplain() {
+	err := fmt.Errorf("reading: %w", io.EOF)
+	res := testerr.Explain(err, testerr.IsType[myError]())
+
+	buf, _ := json.MarshalIndent(res, "", "  ")
+	fmt.Println(string(buf))
+
+	// Output:
+	// {
+	//   "matched": false,
+	//   "got_message": "reading: EOF",
+	//   "got_type": "*fmt.wrapError",
+	//   "expectation": "error tree containing type testerr_test.myError",
+	//   "diff": "got error reading: EOF; want error tree containing type testerr_test.myError; found types [*fmt.wrapError, *errors.errorString]",
+	//   "details": {
+	//     "tree_types": [
+	//       "*fmt.wrapError",
+	//       "*errors.errorString"
+	//     ]
+	//   }
+	// }
+}
+
+func TestExplain(t *testing.T) {
+	errUhOh := errors.New("uh oh")
+
+	tests := []struct {
+		name string
+		err  error
+		want testerr.Want
+		res  testerr.Result
+	}{
+		{
+			name: "nil matched",
+			res: testerr.Result{
+				Matched:     true,
+				GotNil:      true,
+				Expectation: "nil",
+			},
+		},
+		{
+			name: "nil mismatch",
+			err:  errUhOh,
+			res: testerr.Result{
+				GotMessage:  "uh oh",
+				GotType:     "*errors.errorString",
+				Expectation: "nil",
+				Diff:        "got error uh oh; want nil",
+			},
+		},
+		{
+			name: "Is() matched",
+			err:  fmt.Errorf("wrapped: %w", errUhOh),
+			want: testerr.Is(errUhOh),
+			res: testerr.Result{
+				Matched:     true,
+				GotMessage:  "wrapped: uh oh",
+				GotType:     "*fmt.wrapError",
+				Expectation: "error that Is() uh oh",
+			},
+		},
+		{
+			name: "As() with details",
+			err:  myError{42},
+			want: testerr.As(func(myError) string { return "" }),
+			res: testerr.Result{
+				Matched:     true,
+				GotMessage:  "val 42 is not good",
+				GotType:     "testerr_test.myError",
+				Expectation: "error tree containing type testerr_test.myError",
+				Details:     map[string]any{"tree_types": []string{"testerr_test.myError"}},
+			},
+		},
+		{
+			name: "typed nil",
+			err:  newPtrError(false),
+			want: testerr.NotTypedNil(),
+			res: testerr.Result{
+				GotType:     "*testerr_test.ptrError",
+				Expectation: "error that isn't a typed nil",
+				Diff:        "got non-nil error of type *testerr_test.ptrError holding nil pointer; want error that isn't a typed nil",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testerr.Explain(tt.err, tt.want)
+			if diff := cmp.Diff(tt.res, got); diff != "" {
+				t.Errorf("Explain(%v, %s) diff (-want +got):\n%s", tt.err, testerr.Describe(tt.want), diff)
+			}
+			if got, want := got.Diff, testerr.Diff(tt.err, tt.want); got != want {
+				t.Errorf("Explain(…).Diff = %q; Diff(…) = %q; want identical", got, want)
+			}
+
+			buf, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("json.Marshal(Explain(…)) error %v", err)
+			}
+			var roundTrip testerr.Result
+			if err := json.Unmarshal(buf, &roundTrip); err != nil {
+				t.Fatalf("json.Unmarshal(json.Marshal(Explain(…))) error %v", err)
+			}
+			// Unmarshalling into a map[string]any can't recover []string
+			// (for example) so compare via JSON.
+			rebuf, err := json.Marshal(roundTrip)
+			if err != nil {
+				t.Fatalf("json.Marshal(<round-tripped Result>) error %v", err)
+			}
+			if diff := cmp.Diff(string(buf), string(rebuf)); diff != "" {
+				t.Errorf("JSON round trip of Explain(…) diff (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(got, roundTrip, cmp.FilterPath(
+				func(p cmp.Path) bool { return p.String() == "Details" },
+				cmp.Ignore(),
+			)); diff != "" {
+				t.Errorf("JSON round trip of Explain(…) diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package testerr
+
+import "testing"
+
+// SetGOOS overrides the GOOS used by [ByGOOS] until the end of the test.
+func SetGOOS(tb testing.TB, s string) {
+	old := goos
+	goos = s
+	tb.Cleanup(func() { goos = old })
+}
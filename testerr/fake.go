@@ -0,0 +1,118 @@
+package testerr
+
+import "sync/atomic"
+
+// A Fake is an error with configurable behavior, for testing code that handles
+// or classifies errors. Each of its methods calls the respective function
+// field, if non-nil, and records the call, so tests can also assert that the
+// code under test consulted the error as expected.
+//
+// The zero value is an inert error: its message is "testerr.Fake", it neither
+// Is() nor As() anything other than itself, and it wraps nothing. A Fake MUST
+// be used as a pointer and MUST NOT be copied after first use. Its methods are
+// safe for concurrent use, but the function fields MUST NOT be modified once
+// it is in use.
+//
+// [errors.Is] and [errors.As] consult the `Is()` and `As()` methods of a node
+// only after checking the node itself, for equality and assignability
+// respectively, so neither method is called for a match against the Fake
+// itself.
+type Fake struct {
+	// Msg is the message returned by `Error()` if `MsgFunc` is nil.
+	Msg string
+	// MsgFunc, if non-nil, is called by `Error()`. It MAY panic, to test code
+	// that is robust to misbehaving errors.
+	MsgFunc func() string
+	// IsFunc is called by the `Is()` method, which otherwise returns false.
+	IsFunc func(target error) bool
+	// AsFunc is called by the `As()` method, which otherwise returns false. As
+	// with any `As()` method, it MUST populate the target, a non-nil pointer,
+	// when it returns true.
+	AsFunc func(target any) bool
+	// UnwrapFunc is called by the `Unwrap() error` method, which otherwise
+	// returns nil. See [FakeJoin] for `Unwrap() []error`.
+	UnwrapFunc func() error
+
+	errorCalls, isCalls, asCalls, unwrapCalls atomic.Int64
+}
+
+// Error returns the result of `MsgFunc`, or `Msg` if the former is nil.
+func (f *Fake) Error() string {
+	f.errorCalls.Add(1)
+	switch {
+	case f.MsgFunc != nil:
+		return f.MsgFunc()
+	case f.Msg != "":
+		return f.Msg
+	default:
+		return "testerr.Fake"
+	}
+}
+
+// Is returns the result of `IsFunc`, or false if it is nil.
+func (f *Fake) Is(target error) bool {
+	f.isCalls.Add(1)
+	return f.IsFunc != nil && f.IsFunc(target)
+}
+
+// As returns the result of `AsFunc`, or false if it is nil.
+func (f *Fake) As(target any) bool {
+	f.asCalls.Add(1)
+	return f.AsFunc != nil && f.AsFunc(target)
+}
+
+// Unwrap returns the result of `UnwrapFunc`, or nil if it is nil.
+func (f *Fake) Unwrap() error {
+	f.unwrapCalls.Add(1)
+	if f.UnwrapFunc == nil {
+		return nil
+	}
+	return f.UnwrapFunc()
+}
+
+// ErrorCalls returns the number of calls to `Error()`.
+func (f *Fake) ErrorCalls() int { return int(f.errorCalls.Load()) }
+
+// IsCalls returns the number of calls to `Is()`.
+func (f *Fake) IsCalls() int { return int(f.isCalls.Load()) }
+
+// AsCalls returns the number of calls to `As()`.
+func (f *Fake) AsCalls() int { return int(f.asCalls.Load()) }
+
+// UnwrapCalls returns the number of calls to either `Unwrap()` method,
+// including that of a [FakeJoin].
+func (f *Fake) UnwrapCalls() int { return int(f.unwrapCalls.Load()) }
+
+// A FakeJoin is a [Fake] with an `Unwrap() []error` method, like the errors
+// returned by [errors.Join], instead of `Unwrap() error`. It MUST be used as a
+// pointer. The zero value wraps nothing.
+type FakeJoin struct {
+	Fake
+	// UnwrapAllFunc is called by the `Unwrap() []error` method, which
+	// otherwise returns nil. The embedded `Fake.UnwrapFunc` is ignored.
+	UnwrapAllFunc func() []error
+}
+
+// Unwrap returns the result of `UnwrapAllFunc`, or nil if it is nil. It
+// shadows the `Unwrap() error` method of the embedded [Fake].
+func (f *FakeJoin) Unwrap() []error {
+	f.unwrapCalls.Add(1)
+	if f.UnwrapAllFunc == nil {
+		return nil
+	}
+	return f.UnwrapAllFunc()
+}
+
+// A FakeNetError is a [Fake] that additionally has the `Timeout()` and
+// `Temporary()` methods of a [net.Error], returning the respective fields. It
+// MUST be used as a pointer.
+type FakeNetError struct {
+	Fake
+	IsTimeout, IsTemporary bool
+}
+
+// Timeout returns `IsTimeout`.
+func (f *FakeNetError) Timeout() bool { return f.IsTimeout }
+
+// Temporary returns `IsTemporary`.
+func (f *FakeNetError) Temporary() bool { return f.IsTemporary }
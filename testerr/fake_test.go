@@ -0,0 +1,184 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+func ExampleFake() {
+	// An error that Is() io.EOF without wrapping it.
+	fake := &testerr.Fake{
+		Msg:    "fake EOF",
+		IsFunc: func(target error) bool { return target == io.EOF },
+	}
+	err := fmt.Errorf("read: %w", fake)
+
+	fmt.Println(errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF))
+	fmt.Println(fake.IsCalls())
+
+	// Output:
+	// true false
+	// 2
+}
+
+func TestFakeZero(t *testing.T) {
+	f := new(testerr.Fake)
+	var err error = f
+
+	if got, want := err.Error(), "testerr.Fake"; got != want {
+		t.Errorf("%T{}.Error() got %q; want %q", f, got, want)
+	}
+	if errors.Is(err, io.EOF) {
+		t.Errorf("errors.Is(%T{}, io.EOF) got true; want false", f)
+	}
+	if !errors.Is(err, f) {
+		t.Errorf("errors.Is(%T{}, itself) got false; want true", f)
+	}
+	if u := errors.Unwrap(err); u != nil {
+		t.Errorf("errors.Unwrap(%T{}) got %v; want nil", f, u)
+	}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		t.Errorf("errors.As(%T{}, %T) got true; want false", f, &pathErr)
+	}
+
+	// Only Is(io.EOF) consults the method; Is(f) matches by equality.
+	if got, want := f.IsCalls(), 1; got != want {
+		t.Errorf("%T.IsCalls() got %d; want %d", f, got, want)
+	}
+	if got, want := f.AsCalls(), 1; got != want {
+		t.Errorf("%T.AsCalls() got %d; want %d", f, got, want)
+	}
+	if got, want := f.ErrorCalls(), 1; got != want {
+		t.Errorf("%T.ErrorCalls() got %d; want %d", f, got, want)
+	}
+}
+
+func TestFakeHooks(t *testing.T) {
+	errX, errY := errors.New("x"), errors.New("y")
+	wantPath := &fs.PathError{Op: "open", Path: "f", Err: fs.ErrNotExist}
+
+	f := &testerr.Fake{
+		MsgFunc: func() string { return "dynamic" },
+		IsFunc:  func(target error) bool { return target == errX },
+		AsFunc: func(target any) bool {
+			p, ok := target.(**fs.PathError)
+			if ok {
+				*p = wantPath
+			}
+			return ok
+		},
+		UnwrapFunc: func() error { return io.EOF },
+	}
+	err := fmt.Errorf("outer: %w", f)
+
+	checks := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"Error()", err.Error(), "outer: dynamic"},
+		{"errors.Is(…, X)", errors.Is(err, errX), true},
+		{"errors.Is(…, Y)", errors.Is(err, errY), false},
+		// Unwrapped by the Fake, so Is() via the tree as well.
+		{"errors.Is(…, io.EOF)", errors.Is(err, io.EOF), true},
+		{"errors.As(…, *fs.PathError) matched", func() any {
+			var p *fs.PathError
+			return errors.As(err, &p) && p == wantPath
+		}(), true},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s got %v; want %v", c.name, c.got, c.want)
+		}
+	}
+
+	// Each errors.Is() calls the Fake's Is() once, and only the two for which
+	// it returns false go on to call Unwrap().
+	if got, want := f.IsCalls(), 3; got != want {
+		t.Errorf("IsCalls() got %d; want %d", got, want)
+	}
+	if got, want := f.UnwrapCalls(), 2; got != want {
+		t.Errorf("UnwrapCalls() got %d; want %d", got, want)
+	}
+	if got, want := f.AsCalls(), 1; got != want {
+		t.Errorf("AsCalls() got %d; want %d", got, want)
+	}
+
+	if diff := testerr.Diff(err, testerr.ExactChainOf(
+		testerr.Contains("outer"),
+		testerr.MessageIs("dynamic"),
+		testerr.Equals(io.EOF),
+	)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestFakeJoin(t *testing.T) {
+	errX := errors.New("x")
+	f := &testerr.FakeJoin{
+		Fake: testerr.Fake{
+			Msg: "multi",
+			// Ignored as Unwrap() []error shadows Unwrap() error.
+			UnwrapFunc: func() error { return io.ErrUnexpectedEOF },
+		},
+		UnwrapAllFunc: func() []error { return []error{io.EOF, errX} },
+	}
+	var err error = f
+
+	if _, ok := err.(interface{ Unwrap() error }); ok {
+		t.Errorf("%T has Unwrap() error method", f)
+	}
+	if !errors.Is(err, errX) || !errors.Is(err, io.EOF) {
+		t.Errorf("errors.Is(%T, <each of Unwrap() []error>) got false; want true", f)
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("errors.Is(%T, <result of ignored UnwrapFunc>) got true; want false", f)
+	}
+	if diff := testerr.Diff(err, testerr.Joined(testerr.Equals(io.EOF), testerr.Equals(errX))); diff != "" {
+		t.Error(diff)
+	}
+
+	joined := errors.Join(f, errors.New("other"))
+	if diff := testerr.Diff(joined, testerr.IsAll(io.EOF, errX)); diff != "" {
+		t.Error(diff)
+	}
+
+	if got := f.UnwrapCalls(); got == 0 {
+		t.Errorf("%T.UnwrapCalls() got 0; want > 0", f)
+	}
+	if got := new(testerr.FakeJoin).Unwrap(); got != nil {
+		t.Errorf("%T{}.Unwrap() got %v; want nil", f, got)
+	}
+}
+
+func TestFakeNetError(t *testing.T) {
+	f := &testerr.FakeNetError{IsTimeout: true}
+	var _ net.Error = f
+
+	err := fmt.Errorf("dial: %w", f)
+	if diff := testerr.Diff(err, testerr.Implements(func(e net.Error) string {
+		if !e.Timeout() || e.Temporary() {
+			return "Timeout() true and Temporary() false"
+		}
+		return ""
+	})); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestFakePanickingMessage(t *testing.T) {
+	f := &testerr.Fake{MsgFunc: func() string { panic("expensive") }}
+	if diff := testerr.Panics(func() { _ = f.Error() }, testerr.MessageIs("expensive")); diff != "" {
+		t.Error(diff)
+	}
+	if got, want := f.ErrorCalls(), 1; got != want {
+		t.Errorf("ErrorCalls() after panic got %d; want %d", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package testerr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Wrap returns `target` wrapped in one layer per message, the first of which is
+// innermost. Each layer is created with [fmt.Errorf], of the form "msg: %w", so
+// has an `Unwrap() error` method returning the layer below it and the message
+// of the returned error is the messages, outermost first, followed by that of
+// `target`, all separated by ": ". Without any messages, `target` is returned
+// unchanged. Wrapping a nil `target` returns nil.
+func Wrap(target error, msgs ...string) error {
+	if target == nil {
+		return nil
+	}
+	err := target
+	for _, m := range msgs {
+		err = fmt.Errorf("%s: %w", m, err)
+	}
+	return err
+}
+
+// Join is equivalent to [errors.Join], provided for symmetry with [Wrap]. Nil
+// errors are discarded and, if all are nil (including if there are none), Join
+// returns nil. Otherwise the returned error has an `Unwrap() []error` method
+// returning the non-nil errors, in order, even if there is only one; its message
+// is theirs, separated by newlines.
+func Join(errs ...error) error {
+	return errors.Join(errs...)
+}
+
+// A Node specifies an error tree, built by [Tree]. A Node with an `Err` is a
+// leaf holding said error, a Node with a `Join` is the [Join] of what its
+// children build, and a Node with neither is a leaf created with [errors.New]
+// and the `Msg`. A non-empty `Msg` on a leaf with an `Err`, or on a join, adds a
+// layer as if by [Wrap]. The zero Node builds a nil error, which is discarded
+// by any parent's [Join].
+type Node struct {
+	Msg  string
+	Err  error
+	Join []Node
+}
+
+// Tree builds the error tree specified by `root`, allowing mixed wrapping and
+// joining in a single expression. Each call returns new errors, other than
+// those provided as `Err` fields, but their messages and structure are
+// deterministic. Tree panics if any Node has both an `Err` and a `Join`, as
+// that is ambiguous.
+func Tree(root Node) error {
+	switch {
+	case root.Err != nil && root.Join != nil:
+		panic(fmt.Sprintf("testerr.Tree(): Node with both Err (%v) and Join", root.Err))
+	case root.Err != nil:
+		return wrapIfMsg(root.Err, root.Msg)
+	case root.Join != nil:
+		errs := make([]error, len(root.Join))
+		for i, n := range root.Join {
+			errs[i] = Tree(n)
+		}
+		return wrapIfMsg(Join(errs...), root.Msg)
+	case root.Msg != "":
+		return errors.New(root.Msg)
+	default:
+		return nil
+	}
+}
+
+// wrapIfMsg is equivalent to [Wrap] but with an empty message adding no layer.
+func wrapIfMsg(err error, msg string) error {
+	if msg == "" {
+		return err
+	}
+	return Wrap(err, msg)
+}
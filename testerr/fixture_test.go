@@ -0,0 +1,216 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+func ExampleTree() {
+	err := testerr.Tree(testerr.Node{
+		Msg: "handler",
+		Join: []testerr.Node{
+			{Msg: "read body", Err: io.ErrUnexpectedEOF},
+			{Msg: "audit log"},
+			{}, // discarded
+		},
+	})
+	fmt.Printf("%q\n", err)
+	fmt.Println(testerr.Diff(err, testerr.ChainOf(
+		testerr.MessageIs("handler: read body: unexpected EOF\naudit log"),
+		testerr.Joined(
+			testerr.Is(io.ErrUnexpectedEOF),
+			testerr.MessageIs("audit log"),
+		),
+	)) == "")
+
+	// Output:
+	// "handler: read body: unexpected EOF\naudit log"
+	// true
+}
+
+func TestWrap(t *testing.T) {
+	err := testerr.Wrap(io.EOF, "read", "parse", "load")
+
+	tests := []struct {
+		name string
+		want testerr.Want
+	}{
+		{
+			name: "message outermost first",
+			want: testerr.MessageIs("load: parse: read: EOF"),
+		},
+		{
+			name: "Is() target",
+			want: testerr.Is(io.EOF),
+		},
+		{
+			name: "exact chain",
+			want: testerr.ExactChainOf(
+				testerr.HasPrefix("load: "),
+				testerr.HasPrefix("parse: "),
+				testerr.HasPrefix("read: "),
+				testerr.Equals(io.EOF),
+			),
+		},
+		{
+			name: "unwrapped layers",
+			want: testerr.Unwrapped(3, testerr.Equals(io.EOF)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := testerr.Diff(err, tt.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+
+	if got := testerr.Wrap(io.EOF); got != io.EOF {
+		t.Errorf("Wrap(io.EOF) without messages got %v; want io.EOF unchanged", got)
+	}
+	if got := testerr.Wrap(nil, "ignored"); got != nil {
+		t.Errorf("Wrap(nil, …) got %v; want nil", got)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+
+	tests := []struct {
+		name string
+		errs []error
+		want testerr.Want
+	}{
+		{
+			name: "no errors",
+			want: testerr.Nil(),
+		},
+		{
+			name: "all nil",
+			errs: []error{nil, nil},
+			want: testerr.Nil(),
+		},
+		{
+			name: "nil filtered",
+			errs: []error{nil, errA, nil, errB},
+			want: testerr.All(
+				testerr.Joined(testerr.Equals(errA), testerr.Equals(errB)),
+				testerr.MessageIs("a\nb"),
+			),
+		},
+		{
+			name: "single error still joined",
+			errs: []error{errA, nil},
+			want: testerr.All(
+				testerr.Joined(testerr.Equals(errA)),
+				testerr.Not(testerr.Equals(errA)),
+			),
+		},
+		{
+			name: "join of wraps",
+			errs: []error{testerr.Wrap(errA, "x"), testerr.Wrap(errB, "y", "z")},
+			want: testerr.JoinedUnordered(
+				testerr.ExactChainOf(testerr.MessageIs("z: y: b"), testerr.MessageIs("y: b"), testerr.Equals(errB)),
+				testerr.ExactChainOf(testerr.MessageIs("x: a"), testerr.Equals(errA)),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := testerr.Diff(testerr.Join(tt.errs...), tt.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTree(t *testing.T) {
+	tests := []struct {
+		name string
+		root testerr.Node
+		want testerr.Want
+	}{
+		{
+			name: "zero Node",
+			want: testerr.Nil(),
+		},
+		{
+			name: "synthetic leaf",
+			root: testerr.Node{Msg: "leaf"},
+			want: testerr.All(testerr.MessageIs("leaf"), testerr.ExactChainOf(testerr.Contains(""))),
+		},
+		{
+			name: "sentinel leaf",
+			root: testerr.Node{Err: fs.ErrNotExist},
+			want: testerr.Equals(fs.ErrNotExist),
+		},
+		{
+			name: "wrapped sentinel",
+			root: testerr.Node{Msg: "open", Err: fs.ErrNotExist},
+			want: testerr.ExactChainOf(testerr.MessageIs("open: file does not exist"), testerr.Equals(fs.ErrNotExist)),
+		},
+		{
+			name: "join without message",
+			root: testerr.Node{Join: []testerr.Node{{Err: io.EOF}, {Msg: "x"}}},
+			want: testerr.Joined(testerr.Equals(io.EOF), testerr.MessageIs("x")),
+		},
+		{
+			name: "join of zero Nodes",
+			root: testerr.Node{Msg: "empty", Join: []testerr.Node{{}, {}}},
+			want: testerr.Nil(),
+		},
+		{
+			name: "nested mixed shape",
+			root: testerr.Node{
+				Msg: "outer",
+				Join: []testerr.Node{
+					{Msg: "left", Join: []testerr.Node{{Err: io.EOF}, {Err: fs.ErrClosed}}},
+					{Msg: "right", Err: fs.ErrPermission},
+				},
+			},
+			want: testerr.All(
+				testerr.IsAll(io.EOF, fs.ErrClosed, fs.ErrPermission),
+				testerr.MessageIs("outer: left: EOF\nfile already closed\nright: permission denied"),
+				testerr.Unwrapped(1, testerr.Joined(
+					testerr.Unwrapped(1, testerr.JoinedLen(2)),
+					testerr.ExactChainOf(testerr.HasPrefix("right: "), testerr.Equals(fs.ErrPermission)),
+				)),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := testerr.Diff(testerr.Tree(tt.root), tt.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+
+	t.Run("deterministic", func(t *testing.T) {
+		root := testerr.Node{Msg: "a", Join: []testerr.Node{{Msg: "b"}, {Msg: "c", Err: io.EOF}}}
+		first, second := testerr.Tree(root), testerr.Tree(root)
+		if first == second {
+			t.Error("Tree() returned the same error twice; want new errors")
+		}
+		if diff := testerr.Diff(second, testerr.MessageIs(first.Error())); diff != "" {
+			t.Errorf("Tree() of the same Node twice: %s", diff)
+		}
+	})
+
+	t.Run("Err and Join", func(t *testing.T) {
+		diff := testerr.Panics(func() {
+			testerr.Tree(testerr.Node{Err: io.EOF, Join: []testerr.Node{{}}})
+		}, testerr.Contains("both Err"))
+		if diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package testerr
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MatchesFormat checks that the `got` error's string could have been produced
+// by formatting `format`, as with [fmt.Errorf], regardless of the values that
+// were formatted. Every verb is converted to a pattern matching a reasonable
+// token for that verb (e.g. an integer for %d), with the remainder of the
+// format matched literally and the entire message anchored. A nil error never
+// matches.
+//
+// Supported verbs are %v, %s, %w, %T, %q, %d, %b, %o, %x, %X, %c, %U, %e, %E,
+// %f, %F, %g, %G, %t and %p, optionally with flags, width and precision, along
+// with the literal %%. Any other verb, or an explicit argument index, results
+// in a diff. Diffs report `format` and never the derived regular expression.
+func MatchesFormat(format string) Want {
+	desc := fmt.Sprintf("message matching format %q", format)
+	re, err := formatRegexp(format)
+
+	return &described{
+		desc: desc,
+		diff: func(got error) string {
+			if err != nil {
+				return DiffMessage(got, "%s but format is unsupported: %v", desc, err)
+			}
+			if got == nil {
+				return DiffMessage(got, "%s", desc)
+			}
+			if msg, ok := message(got); ok && re.MatchString(msg) {
+				return ""
+			}
+			return DiffMessage(got, "%s", desc)
+		},
+	}
+}
+
+// formatVerbPatterns maps printf verbs to regular expressions matching their
+// unpadded output, to which [verbPattern] adds padding.
+var formatVerbPatterns = map[byte]string{
+	'v': `.*?`,
+	's': `.*?`,
+	'w': `.*?`,
+	'T': `.*?`,
+	'q': `(?:"(?:[^"\\]|\\.)*"|` + "`[^`]*`" + `|'(?:[^'\\]|\\.)+')`,
+	'd': `[-+ ]?[0-9]+`,
+	'b': `[-+ ]?[01]+`,
+	'o': `[-+ ]?(?:0o?)?[0-7]+`,
+	'x': `[-+ ]?(?:0x)?[0-9a-f]+`,
+	'X': `[-+ ]?(?:0X)?[0-9A-F]+`,
+	'c': `.`,
+	'U': `U\+[0-9A-F]{4,}(?: '.')?`,
+	'e': `[-+ ]?(?:[0-9]+(?:\.[0-9]*)?e[-+][0-9]+|Inf|NaN)`,
+	'E': `[-+ ]?(?:[0-9]+(?:\.[0-9]*)?E[-+][0-9]+|Inf|NaN)`,
+	'f': `[-+ ]?(?:[0-9]+(?:\.[0-9]*)?|Inf|NaN)`,
+	'F': `[-+ ]?(?:[0-9]+(?:\.[0-9]*)?|Inf|NaN)`,
+	'g': `[-+ ]?(?:[0-9]+(?:\.[0-9]*)?(?:e[-+][0-9]+)?|Inf|NaN)`,
+	'G': `[-+ ]?(?:[0-9]+(?:\.[0-9]*)?(?:E[-+][0-9]+)?|Inf|NaN)`,
+	't': `(?:true|false)`,
+	'p': `0x[0-9a-f]+`,
+}
+
+// verbPattern returns the pattern for `verb` padded as by its `flags`: with
+// trailing spaces for the - flag, otherwise leading spaces. Zero padding, after
+// any sign, is already matched by the digits of numeric patterns.
+func verbPattern(verb byte, flags string) (string, bool) {
+	p, ok := formatVerbPatterns[verb]
+	if !ok {
+		return "", false
+	}
+	if strings.IndexByte(flags, '-') != -1 {
+		return `(?:` + p + `) *`, true
+	}
+	return ` *(?:` + p + `)`, true
+}
+
+// formatRegexp converts a printf-style format into an anchored regular
+// expression matching any of its possible outputs.
+func formatRegexp(format string) (*regexp.Regexp, error) {
+	var re strings.Builder
+	re.WriteString(`^(?s:`)
+
+	for i := 0; i < len(format); i++ {
+		pct := strings.IndexByte(format[i:], '%')
+		if pct == -1 {
+			re.WriteString(regexp.QuoteMeta(format[i:]))
+			break
+		}
+		re.WriteString(regexp.QuoteMeta(format[i : i+pct]))
+		start := i + pct
+		i = start + 1
+
+		// Flags, width, and precision, in that order.
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) != -1 {
+			i++
+		}
+		flags := format[start+1 : i]
+		for i < len(format) && (isDigit(format[i]) || format[i] == '*') {
+			i++
+		}
+		if i < len(format) && format[i] == '.' {
+			i++
+			for i < len(format) && (isDigit(format[i]) || format[i] == '*') {
+				i++
+			}
+		}
+
+		if i == len(format) {
+			return nil, fmt.Errorf("incomplete verb %q at end of format", format[start:])
+		}
+		switch verb := format[i]; verb {
+		case '%':
+			if i != start+1 {
+				return nil, fmt.Errorf("%%%% with flags, width, or precision at byte %d", start)
+			}
+			re.WriteString(`%`)
+		case '[':
+			return nil, fmt.Errorf("explicit argument index at byte %d", start)
+		default:
+			pattern, ok := verbPattern(verb, flags)
+			if !ok {
+				return nil, fmt.Errorf("verb %q at byte %d", format[start:i+1], start)
+			}
+			re.WriteString(`(?:` + pattern + `)`)
+		}
+	}
+
+	re.WriteString(`)$`)
+	return regexp.Compile(re.String())
+}
+
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+// Formats checks the output of formatting the `got` error with `verb`, as by
+// [fmt.Sprintf], against `want`. The output is checked, with [Diff], as an
+// error of which it is the string, so message-based [Want]s such as [Contains]
+// and [MatchesRegexp] compose while a nil `want` never matches. This allows
+// assertions on detail that a [fmt.Formatter] includes with, for example,
+// `%+v` but that `Error()` omits. A nil error never matches.
+//
+// The `verb` MUST be a single verb, optionally with flags, width and
+// precision. Any other format, or formatting that [fmt] reports as failed,
+// including because of a panic, results in a diff. Diffs show both the `%v`
+// and the `verb` outputs.
+func Formats(verb string, want Want) Want {
+	desc := fmt.Sprintf("%s output matching %s", verb, Describe(want))
+	valid := validVerb(verb)
+
+	return &described{
+		desc: desc,
+		diff: func(got error) string {
+			if !valid {
+				return DiffMessage(got, "Formats() of a single verb; got %q", verb)
+			}
+			if got == nil {
+				return DiffMessage(got, "%s", desc)
+			}
+
+			out, ok := safeSprintf(verb, got)
+			var d string
+			if ok {
+				if d = Diff(errors.New(out), want); d == "" {
+					return ""
+				}
+			}
+			plain, plainOK := safeSprintf("%v", got)
+			if !ok {
+				return DiffMessage(got, "%s; formatting failed: %%v output %q and %s output %q", desc, plain, verb, out)
+			}
+			if !plainOK {
+				plain = fmt.Sprintf("<formatting failed: %s>", plain)
+			}
+			return DiffMessage(got, "%s; %%v output %q and %s output %q:\n\t%s", desc, plain, verb, out, d)
+		},
+	}
+}
+
+// validVerb reports whether `verb` is a single formatting verb, with optional
+// flags, width and precision but not an argument index.
+func validVerb(verb string) bool {
+	if len(verb) < 2 || verb[0] != '%' {
+		return false
+	}
+	for i := 1; i < len(verb)-1; i++ {
+		if !strings.ContainsRune("+-# 0.", rune(verb[i])) && !isDigit(verb[i]) {
+			return false
+		}
+	}
+	last := verb[len(verb)-1]
+	return ('a' <= last && last <= 'z') || ('A' <= last && last <= 'Z')
+}
+
+// safeSprintf formats `err`, reporting false if [fmt] reports that doing so
+// failed; e.g. because of a bad verb or a panicking method. Panics that [fmt]
+// propagates, rather than reports, are also recovered.
+func safeSprintf(verb string, err error) (out string, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			out, ok = fmt.Sprintf("panic: %v", r), false
+		}
+	}()
+	out = fmt.Sprintf(verb, err)
+	return out, !strings.Contains(out, "%!")
+}
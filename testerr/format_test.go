@@ -0,0 +1,282 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+func ExampleMatchesFormat() {
+	const format = "dial %s: retry %d exceeded"
+	want := testerr.MatchesFormat(format)
+
+	for _, err := range []error{
+		fmt.Errorf(format, "10.0.0.1:443", 3),
+		fmt.Errorf(format, "[::1]:8080", 42),
+		fmt.Errorf("dial %s: retry %s exceeded", "10.0.0.1:443", "many"),
+	} {
+		if diff := testerr.Diff(err, want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// <empty>
+	// got error dial 10.0.0.1:443: retry many exceeded; want message matching format "dial %s: retry %d exceeded"
+}
+
+func TestMatchesFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		err      error
+		wantDiff bool
+	}{
+		{
+			name:   "escaped percent",
+			format: "%d%% complete",
+			err:    fmt.Errorf("%d%% complete", 99),
+		},
+		{
+			name:     "escaped percent is literal",
+			format:   "%d%% complete",
+			err:      errors.New("99 complete"),
+			wantDiff: true,
+		},
+		{
+			name:   "adjacent verbs",
+			format: "%s%d%q",
+			err:    fmt.Errorf("%s%d%q", "abc", 123, `x"y`),
+		},
+		{
+			name:   "width and precision",
+			format: "[%5d] [%-8s] [%.2f] [%08.3f] [%+d] [%x] [%#x]",
+			err:    fmt.Errorf("[%5d] [%-8s] [%.2f] [%08.3f] [%+d] [%x] [%#x]", 7, "ab", 3.14159, 2.5, 9, 255, 255),
+		},
+		{
+			name:   "left-justified",
+			format: "%-5d|",
+			err:    fmt.Errorf("%-5d|", 42),
+		},
+		{
+			name:   "left-justified hex",
+			format: "[%-8x]",
+			err:    fmt.Errorf("[%-8x]", 255),
+		},
+		{
+			name:   "left-justified with space flag",
+			format: "[% -5d]",
+			err:    fmt.Errorf("[% -5d]", 42),
+		},
+		{
+			name:     "left-justified rejects leading padding",
+			format:   "[%-5d]",
+			err:      errors.New("[   42]"),
+			wantDiff: true,
+		},
+		{
+			name:   "zero padded",
+			format: "[%05d] [%05d]",
+			err:    fmt.Errorf("[%05d] [%05d]", 42, -42),
+		},
+		{
+			name:   "explicit sign",
+			format: "%+d %+d",
+			err:    fmt.Errorf("%+d %+d", 42, -42),
+		},
+		{
+			name:   "wrapped message with regexp metacharacters",
+			format: "op %s: %w",
+			err:    fmt.Errorf("op %s: %w", "x", errors.New(`bad (a+b)* [^$] \d`)),
+		},
+		{
+			name:   "literal regexp metacharacters in format",
+			format: "(%d+%d)*^$",
+			err:    fmt.Errorf("(%d+%d)*^$", 1, 2),
+		},
+		{
+			name:     "anchored at start",
+			format:   "retry %d",
+			err:      errors.New("final retry 3"),
+			wantDiff: true,
+		},
+		{
+			name:     "anchored at end",
+			format:   "retry %d",
+			err:      errors.New("retry 3 failed"),
+			wantDiff: true,
+		},
+		{
+			name:   "multi-line value",
+			format: "lines: %v",
+			err:    fmt.Errorf("lines: %v", "a\nb"),
+		},
+		{
+			name:     "integer verb rejects non-integers",
+			format:   "n=%d",
+			err:      errors.New("n=abc"),
+			wantDiff: true,
+		},
+		{
+			name:     "nil error",
+			format:   "",
+			err:      nil,
+			wantDiff: true,
+		},
+		{
+			name:     "unsupported verb",
+			format:   "%y",
+			err:      errors.New("y"),
+			wantDiff: true,
+		},
+		{
+			name:     "trailing percent",
+			format:   "100%",
+			err:      errors.New("100%"),
+			wantDiff: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := testerr.Diff(tt.err, testerr.MatchesFormat(tt.format))
+			if got := diff != ""; got != tt.wantDiff {
+				t.Errorf("Diff(%q, MatchesFormat(%q)) got diff %q; want non-empty = %t", tt.err, tt.format, diff, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func TestMatchesFormatUnsupported(t *testing.T) {
+	got := testerr.Diff(errors.New("x"), testerr.MatchesFormat("a %[1]d"))
+	const want = `got error x; want message matching format "a %[1]d" but format is unsupported: explicit argument index at byte 2`
+	if got != want {
+		t.Errorf("Diff(…, MatchesFormat(<explicit index>)) got %q; want %q", got, want)
+	}
+}
+
+// detailedError includes its attributes only when formatted with %+v.
+type detailedError struct {
+	msg   string
+	attrs string
+}
+
+func (e detailedError) Error() string { return e.msg }
+
+func (e detailedError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		fmt.Fprintf(s, "%s [%s]", e.msg, e.attrs)
+	case verb == 'v' || verb == 's':
+		fmt.Fprint(s, e.msg)
+	default:
+		fmt.Fprintf(s, "%%!%c(detailedError)", verb)
+	}
+}
+
+// panickingFormatter panics when formatted.
+type panickingFormatter struct{}
+
+func (panickingFormatter) Error() string          { return "panicking" }
+func (panickingFormatter) Format(fmt.State, rune) { panic("boom") }
+
+func TestFormats(t *testing.T) {
+	detailed := detailedError{msg: "query failed", attrs: "table=users"}
+
+	tests := []struct {
+		name     string
+		err      error
+		want     testerr.Want
+		wantDiff string // following "want"; empty if no diff is expected
+	}{
+		{
+			name: "%+v detail",
+			err:  detailed,
+			want: testerr.Formats("%+v", testerr.Contains("table=users")),
+		},
+		{
+			name: "%v without detail",
+			err:  detailed,
+			want: testerr.Formats("%v", testerr.MessageIs("query failed")),
+		},
+		{
+			name: "wrapped Formatter isn't consulted",
+			err:  fmt.Errorf("db: %w", detailed),
+			want: testerr.Formats("%+v", testerr.MessageIs("db: query failed")),
+		},
+		{
+			name:     "mismatch shows both outputs",
+			err:      detailed,
+			want:     testerr.Formats("%+v", testerr.Contains("table=orders")),
+			wantDiff: "%+v output matching containing substring \"table=orders\"; %v output \"query failed\" and %+v output \"query failed [table=users]\":\n\tgot error query failed [table=users]; want containing substring \"table=orders\"",
+		},
+		{
+			name: "not a Formatter",
+			err:  errors.New("plain"),
+			want: testerr.Formats("%+v", testerr.MessageIs("plain")),
+		},
+		{
+			name: "%q of non-Formatter",
+			err:  errors.New("plain"),
+			want: testerr.Formats("%q", testerr.MessageIs(`"plain"`)),
+		},
+		{
+			name: "width and precision",
+			err:  errors.New("plain"),
+			want: testerr.Formats("%8.3s", testerr.MessageIs("     pla")),
+		},
+		{
+			name:     "verb unsupported by Formatter",
+			err:      detailed,
+			want:     testerr.Formats("%d", testerr.Contains("")),
+			wantDiff: `%d output matching containing substring ""; formatting failed: %v output "query failed" and %d output "%!d(detailedError)"`,
+		},
+		{
+			name:     "verb unsupported by non-Formatter",
+			err:      errors.New("plain"),
+			want:     testerr.Formats("%d", testerr.Contains("")),
+			wantDiff: `%d output matching containing substring ""; formatting failed: %v output "plain" and %d output "&{%!d(string=plain)}"`,
+		},
+		{
+			name:     "panicking Formatter",
+			err:      panickingFormatter{},
+			want:     testerr.Formats("%+v", testerr.Contains("")),
+			wantDiff: `%+v output matching containing substring ""; formatting failed: %v output "%!v(PANIC=Format method: boom)" and %+v output "%!v(PANIC=Format method: boom)"`,
+		},
+		{
+			name:     "nil error",
+			want:     testerr.Formats("%+v", testerr.Contains("")),
+			wantDiff: `%+v output matching containing substring ""`,
+		},
+		{
+			name:     "nil Want",
+			err:      detailed,
+			want:     testerr.Formats("%v", nil),
+			wantDiff: "%v output matching nil; %v output \"query failed\" and %v output \"query failed\":\n\tgot error query failed; want nil",
+		},
+	}
+	for _, verb := range []string{"", "%", "v", "%v %v", "%[1]v", "%%", "%v\n"} {
+		tests = append(tests, struct {
+			name     string
+			err      error
+			want     testerr.Want
+			wantDiff string // following "want"; empty if no diff is expected
+		}{
+			name:     fmt.Sprintf("invalid verb %q", verb),
+			err:      detailed,
+			want:     testerr.Formats(verb, testerr.Contains("")),
+			wantDiff: fmt.Sprintf("Formats() of a single verb; got %q", verb),
+		})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkDiff(t, tt.err, tt.want, tt.wantDiff)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package testerr
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// A TreeSpec configures the shape of error trees built by [GenerateTree].
+type TreeSpec struct {
+	// MaxDepth is the maximum number of wrapping or joining layers above any
+	// leaf. A value of zero results in a single leaf.
+	MaxDepth int
+	// MaxFanOut is the maximum number of errors joined by an [errors.Join]
+	// node, of which there are always at least two. A value less than two
+	// disables joining.
+	MaxFanOut int
+	// Sentinels are errors that MAY be chosen as leaves, instead of a
+	// synthetic error. A leaf is equally likely to be a sentinel or synthetic,
+	// and all sentinels are equally likely.
+	Sentinels []error
+}
+
+// A GeneratedTree is the result of [GenerateTree].
+type GeneratedTree struct {
+	// Err is the root of the tree.
+	Err error
+	// Sentinels are the members of [TreeSpec.Sentinels] that are in the tree,
+	// deduplicated and in the pre-order, left-to-right order of their first
+	// appearance.
+	Sentinels []error
+}
+
+// GenerateTree builds a pseudo-random error tree, for use in fuzz and property
+// tests of code that handles errors. For a given source of randomness and
+// spec, the tree's shape and messages are deterministic, so the same seed
+// reproduces the same tree.
+//
+// Interior nodes either wrap a single error with [fmt.Errorf] and the %w verb,
+// or join multiple errors with [errors.Join]. Synthetic leaves and wrapping
+// layers have messages numbered in the order in which they are created. The
+// returned [GeneratedTree.Sentinels] MAY be used to derive expectations, for
+// example with [IsAll].
+func GenerateTree(r *rand.Rand, spec TreeSpec) GeneratedTree {
+	g := &treeGenerator{r: r, spec: spec}
+	return GeneratedTree{
+		Err:       g.node(spec.MaxDepth),
+		Sentinels: g.sentinels,
+	}
+}
+
+type treeGenerator struct {
+	r         *rand.Rand
+	spec      TreeSpec
+	sentinels []error
+	leaves    int
+	wraps     int
+}
+
+func (g *treeGenerator) node(depth int) error {
+	const (
+		leaf = iota
+		wrap
+		join
+	)
+	kind := leaf
+	if depth > 0 {
+		n := 2
+		if g.spec.MaxFanOut >= 2 {
+			n++
+		}
+		kind = g.r.IntN(n)
+	}
+
+	switch kind {
+	case wrap:
+		g.wraps++
+		id := g.wraps
+		return fmt.Errorf("wrap %d: %w", id, g.node(depth-1))
+
+	case join:
+		errs := make([]error, 2+g.r.IntN(g.spec.MaxFanOut-1))
+		for i := range errs {
+			errs[i] = g.node(depth - 1)
+		}
+		return errors.Join(errs...)
+	}
+
+	if n := len(g.spec.Sentinels); n > 0 && g.r.IntN(2) == 0 {
+		s := g.spec.Sentinels[g.r.IntN(n)]
+		if !slices.Contains(g.sentinels, s) {
+			g.sentinels = append(g.sentinels, s)
+		}
+		return s
+	}
+	g.leaves++
+	return fmt.Errorf("leaf %d", g.leaves)
+}
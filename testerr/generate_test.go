@@ -0,0 +1,107 @@
+package testerr_test
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+var (
+	errRetryable = errors.New("retryable")
+	errFatal     = errors.New("fatal")
+	errTimeout   = errors.New("timeout")
+)
+
+// classify is an example of error-handling code under test.
+func classify(err error) string {
+	switch {
+	case errors.Is(err, errFatal):
+		return "fatal"
+	case errors.Is(err, errRetryable), errors.Is(err, errTimeout):
+		return "retry"
+	default:
+		return "unknown"
+	}
+}
+
+func FuzzGenerateTree(f *testing.F) {
+	for seed := range uint64(10) {
+		f.Add(seed)
+	}
+
+	sentinels := []error{errRetryable, errFatal, errTimeout}
+	spec := testerr.TreeSpec{
+		MaxDepth:  5,
+		MaxFanOut: 3,
+		Sentinels: sentinels,
+	}
+
+	f.Fuzz(func(t *testing.T, seed uint64) {
+		tree := testerr.GenerateTree(rand.New(rand.NewPCG(seed, 0)), spec)
+
+		if len(tree.Sentinels) > 0 {
+			if diff := testerr.Diff(tree.Err, testerr.IsAll(tree.Sentinels...)); diff != "" {
+				t.Errorf("Diff(GenerateTree(…).Err, IsAll(GenerateTree(…).Sentinels...)) %s", diff)
+			}
+		}
+		for _, s := range sentinels {
+			if slices.Contains(tree.Sentinels, s) {
+				continue
+			}
+			if diff := testerr.Diff(tree.Err, testerr.Not(testerr.Is(s))); diff != "" {
+				t.Errorf("Diff(GenerateTree(…).Err, Not(Is(<sentinel not reported as in tree>))) %s", diff)
+			}
+		}
+
+		// Derive the expected classification from the reported sentinels.
+		want := "unknown"
+		switch {
+		case slices.Contains(tree.Sentinels, errFatal):
+			want = "fatal"
+		case len(tree.Sentinels) > 0:
+			want = "retry"
+		}
+		if got := classify(tree.Err); got != want {
+			t.Errorf("classify(%q) got %q; want %q", tree.Err, got, want)
+		}
+	})
+}
+
+func TestGenerateTreeDeterministic(t *testing.T) {
+	spec := testerr.TreeSpec{
+		MaxDepth:  6,
+		MaxFanOut: 4,
+		Sentinels: []error{errRetryable, errFatal},
+	}
+
+	for seed := range uint64(20) {
+		gen := func() testerr.GeneratedTree {
+			return testerr.GenerateTree(rand.New(rand.NewPCG(seed, 0)), spec)
+		}
+		a, b := gen(), gen()
+		if a.Err.Error() != b.Err.Error() || !slices.Equal(a.Sentinels, b.Sentinels) {
+			t.Errorf("GenerateTree(<seed %d>, …) not deterministic; got %q and %q", seed, a.Err, b.Err)
+		}
+	}
+}
+
+func TestGenerateTreeShape(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	leaf := testerr.GenerateTree(r, testerr.TreeSpec{MaxDepth: 0, MaxFanOut: 5})
+	if diff := testerr.Diff(leaf.Err, testerr.MessageIs("leaf 1")); diff != "" {
+		t.Errorf("GenerateTree(…, <zero depth>).Err %s", diff)
+	}
+
+	for range 100 {
+		tree := testerr.GenerateTree(r, testerr.TreeSpec{MaxDepth: 4, MaxFanOut: 1})
+		for err := tree.Err; err != nil; err = errors.Unwrap(err) {
+			if _, ok := err.(interface{ Unwrap() []error }); ok {
+				t.Fatalf("GenerateTree(…, {MaxFanOut: 1}) returned tree containing a join: %q", tree.Err)
+			}
+		}
+	}
+}
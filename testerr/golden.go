@@ -0,0 +1,98 @@
+package testerr
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// updateGolden is namespaced to avoid conflicting with the common -update flag
+// registered by many test packages.
+var updateGolden = flag.Bool("testerr.update", false, "update golden files checked by testerr.MatchesGolden()")
+
+// MatchesGolden checks that the `got` error's message is equal to the contents
+// of the golden file at `path`, conventionally under `testdata/`. The file's
+// contents have a single trailing newline removed, if present, before
+// comparison. On mismatch, the diff includes a line-by-line comparison of the
+// two.
+//
+// If the -testerr.update flag is set, the file is instead (over)written with
+// the message and a trailing newline, and the returned [Want] matches. Files
+// are replaced atomically so concurrent updates, even of the same file from
+// parallel subtests, don't corrupt them. A nil `got` error always results in a
+// diff and never updates the file.
+func MatchesGolden(t testing.TB, path string) Want {
+	t.Helper()
+	desc := fmt.Sprintf("message equal to golden file %q", path)
+
+	return &described{
+		desc: desc,
+		diff: func(got error) string {
+			if got == nil {
+				return DiffMessage(got, "%s; nil error has no message to compare", desc)
+			}
+			msg, ok := message(got)
+			if !ok {
+				return DiffMessage(got, "%s", desc)
+			}
+
+			if *updateGolden {
+				if err := writeGolden(path, msg); err != nil {
+					return DiffMessage(got, "%s; updating: %v", desc, err)
+				}
+				t.Logf("Updated golden file %q", path)
+				return ""
+			}
+
+			buf, err := os.ReadFile(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				return DiffMessage(got, "%s; file doesn't exist; run with -testerr.update to create it", desc)
+			}
+			if err != nil {
+				return DiffMessage(got, "%s; reading: %v", desc, err)
+			}
+			want := strings.TrimSuffix(string(buf), "\n")
+			if want == msg {
+				return ""
+			}
+			return DiffMessage(got, "%s; diff (-golden +got):\n%s", desc, cmp.Diff(want, msg))
+		},
+	}
+}
+
+// writeGolden atomically writes `msg`, followed by a newline, to `path`,
+// creating any parent directories.
+func writeGolden(path, msg string) (retErr error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	if _, err := f.WriteString(msg + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
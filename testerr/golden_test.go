@@ -0,0 +1,145 @@
+package testerr_test
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// setUpdateGolden sets the -testerr.update flag for the duration of the test.
+func setUpdateGolden(t *testing.T) {
+	t.Helper()
+	if err := flag.Set("testerr.update", "true"); err != nil {
+		t.Fatalf("flag.Set(testerr.update) error %v", err)
+	}
+	t.Cleanup(func() {
+		if err := flag.Set("testerr.update", "false"); err != nil {
+			t.Errorf("flag.Set(testerr.update) error %v", err)
+		}
+	})
+}
+
+func TestMatchesGolden(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		path string
+		// wantDiff are substrings of the expected diff, or empty if none
+		wantDiff []string
+	}{
+		{
+			name: "match with trailing newline",
+			err:  errors.New("bad flag"),
+			path: write("newline.golden", "bad flag\n"),
+		},
+		{
+			name: "match without trailing newline",
+			err:  errors.New("bad flag"),
+			path: write("no-newline.golden", "bad flag"),
+		},
+		{
+			name: "only one trailing newline removed",
+			err:  errors.New("bad flag\n"),
+			path: write("double-newline.golden", "bad flag\n\n"),
+		},
+		{
+			name:     "mismatch",
+			err:      errors.New("usage: cli [flags]\n\t-v: verbose\n\t-x: experimental"),
+			path:     write("mismatch.golden", "usage: cli [flags]\n\t-v: verbose\n"),
+			wantDiff: []string{"want message equal to golden file", "diff (-golden +got)", "-x: experimental"},
+		},
+		{
+			name:     "nil error",
+			err:      nil,
+			path:     write("nil.golden", "\n"),
+			wantDiff: []string{"got error <nil>", "nil error has no message to compare"},
+		},
+		{
+			name:     "missing file",
+			err:      errors.New("bad flag"),
+			path:     filepath.Join(dir, "missing.golden"),
+			wantDiff: []string{"file doesn't exist; run with -testerr.update"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := testerr.Diff(tt.err, testerr.MatchesGolden(t, tt.path))
+			if len(tt.wantDiff) == 0 {
+				if diff != "" {
+					t.Errorf("Diff(%q, MatchesGolden(%q)) got %q; want empty", tt.err, tt.path, diff)
+				}
+				return
+			}
+			for _, want := range tt.wantDiff {
+				if !strings.Contains(diff, want) {
+					t.Errorf("Diff(%v, MatchesGolden(%q)) got %q; want containing %q", tt.err, tt.path, diff, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchesGoldenUpdate(t *testing.T) {
+	setUpdateGolden(t)
+	dir := t.TempDir()
+
+	const n = 8
+	t.Run("parallel", func(t *testing.T) {
+		for i := range n {
+			t.Run(fmt.Sprint(i), func(t *testing.T) {
+				t.Parallel()
+				// Every file is updated concurrently with every other, and
+				// the shared one repeatedly.
+				for _, name := range []string{fmt.Sprintf("nested/%d.golden", i), "shared.golden"} {
+					path := filepath.Join(dir, name)
+					for range 10 {
+						testerr.Assert(t, errors.New("message from "+name), testerr.MatchesGolden(t, path), "MatchesGolden(%q) with -testerr.update;", path)
+					}
+				}
+			})
+		}
+	})
+
+	for i := range n {
+		path := filepath.Join(dir, "nested", fmt.Sprintf("%d.golden", i))
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("message from nested/%d.golden\n", i); string(got) != want {
+			t.Errorf("Updated golden file %q contains %q; want %q", path, got, want)
+		}
+	}
+	if got, err := os.ReadFile(filepath.Join(dir, "shared.golden")); err != nil || string(got) != "message from shared.golden\n" {
+		t.Errorf("Updated shared golden file contains %q (read error %v); want %q", got, err, "message from shared.golden\n")
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(tmp) != 0 {
+		t.Errorf("Temporary files %q remain after updates", tmp)
+	}
+
+	t.Run("nil error not written", func(t *testing.T) {
+		path := filepath.Join(dir, "nil.golden")
+		if diff := testerr.Diff(nil, testerr.MatchesGolden(t, path)); diff == "" {
+			t.Errorf("Diff(nil, MatchesGolden(%q)) with -testerr.update got empty diff", path)
+		}
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("os.Stat(%q) after Diff(nil, …) with -testerr.update got error %v; want %v", path, err, os.ErrNotExist)
+		}
+	})
+}
@@ -0,0 +1,12 @@
+module github.com/arr4n/shed/testerr/gomockerr
+
+go 1.24.8
+
+replace github.com/arr4n/shed => ../..
+
+require (
+	github.com/arr4n/shed v0.0.0-00010101000000-000000000000
+	go.uber.org/mock v0.6.0
+)
+
+require github.com/google/go-cmp v0.7.0 // indirect
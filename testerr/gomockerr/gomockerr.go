@@ -0,0 +1,66 @@
+// Package gomockerr adapts [testerr.Want] values for use as argument matchers
+// in [gomock] expectations. It is a separate module so that the testerr package
+// doesn't depend on gomock.
+package gomockerr
+
+import (
+	"fmt"
+
+	"github.com/arr4n/shed/testerr"
+	"go.uber.org/mock/gomock"
+)
+
+// Matcher returns a [gomock.Matcher] that matches an error argument if and only
+// if [testerr.Diff] of it and `want` is empty. As with [testerr.Diff], a nil
+// `want` expects a nil error, which includes a nil argument. Arguments that are
+// neither errors nor nil never match.
+//
+// The returned value also implements [gomock.GotFormatter], reporting the
+// canonical diff for mismatched arguments.
+func Matcher(want testerr.Want) gomock.Matcher {
+	return matcher{want}
+}
+
+type matcher struct {
+	want testerr.Want
+}
+
+var _ gomock.GotFormatter = matcher{}
+
+// diff returns the [testerr.Diff] of the argument, and whether it is an error
+// (or nil) at all.
+func (m matcher) diff(x any) (string, bool) {
+	if x == nil {
+		return testerr.Diff(nil, m.want), true
+	}
+	err, ok := x.(error)
+	if !ok {
+		return "", false
+	}
+	return testerr.Diff(err, m.want), true
+}
+
+// Matches implements [gomock.Matcher].
+func (m matcher) Matches(x any) bool {
+	diff, ok := m.diff(x)
+	return ok && diff == ""
+}
+
+// String implements [gomock.Matcher], returning the [testerr.Describe] value of
+// the [testerr.Want].
+func (m matcher) String() string {
+	return testerr.Describe(m.want)
+}
+
+// Got implements [gomock.GotFormatter].
+func (m matcher) Got(got any) string {
+	diff, ok := m.diff(got)
+	switch {
+	case !ok:
+		return fmt.Sprintf("%v (%T), which isn't an error", got, got)
+	case diff == "":
+		return fmt.Sprintf("%v", got)
+	default:
+		return diff
+	}
+}
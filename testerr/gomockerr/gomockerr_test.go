@@ -0,0 +1,136 @@
+package gomockerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+	"github.com/arr4n/shed/testerr/gomockerr"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    testerr.Want
+		arg     any
+		matches bool
+		// got is the expected return of Got(arg).
+		got string
+	}{
+		{
+			name:    "match",
+			want:    testerr.Is(io.EOF),
+			arg:     fmt.Errorf("read: %w", io.EOF),
+			matches: true,
+			got:     "read: EOF",
+		},
+		{
+			name: "mismatch",
+			want: testerr.Is(io.EOF),
+			arg:  errors.New("uh oh"),
+			got:  "got error uh oh; want error that Is() EOF",
+		},
+		{
+			name: "nil argument",
+			want: testerr.Is(io.EOF),
+			arg:  nil,
+			got:  "got error <nil>; want error that Is() EOF",
+		},
+		{
+			name:    "nil Want with nil argument",
+			want:    nil,
+			arg:     nil,
+			matches: true,
+			got:     "<nil>",
+		},
+		{
+			name: "nil Want with non-nil error",
+			want: nil,
+			arg:  io.EOF,
+			got:  "got error EOF; want nil",
+		},
+		{
+			name: "non-error argument",
+			want: testerr.Contains("42"),
+			arg:  42,
+			got:  "42 (int), which isn't an error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := gomockerr.Matcher(tt.want)
+			if got := m.Matches(tt.arg); got != tt.matches {
+				t.Errorf("Matcher(%s).Matches(%v) got %t; want %t", m, tt.arg, got, tt.matches)
+			}
+			if got := m.(gomock.GotFormatter).Got(tt.arg); got != tt.got {
+				t.Errorf("Matcher(%s).Got(%v) got %q; want %q", m, tt.arg, got, tt.got)
+			}
+		})
+	}
+}
+
+func TestMatcherString(t *testing.T) {
+	for want, desc := range map[testerr.Want]string{
+		nil:                      "nil",
+		testerr.Is(io.EOF):       "error that Is() EOF",
+		testerr.Contains("oops"): `containing substring "oops"`,
+	} {
+		if got := gomockerr.Matcher(want).String(); got != desc {
+			t.Errorf("Matcher(%s).String() got %q; want %q", desc, got, desc)
+		}
+	}
+}
+
+// reporter is a [gomock.TestReporter] that records fatal errors instead of
+// failing the test. Like [testing.T.Fatalf], its Fatalf stops the calling
+// goroutine.
+type reporter struct {
+	testing.TB
+	fatals []string
+}
+
+func (r *reporter) Fatalf(format string, args ...any) {
+	r.fatals = append(r.fatals, fmt.Sprintf(format, args...))
+	runtime.Goexit()
+}
+
+// reportable is a stand-in for a mocked type, allowing [gomock.Controller] to
+// be used directly, without generated code.
+type reportable struct{}
+
+func (reportable) Report(error) {}
+
+func TestWithController(t *testing.T) {
+	rep := &reporter{TB: t}
+	ctrl := gomock.NewController(rep)
+	recv := reportable{}
+
+	ctrl.RecordCall(recv, "Report", gomockerr.Matcher(testerr.Is(io.EOF))).Times(1)
+	ctrl.RecordCall(recv, "Report", gomockerr.Matcher(nil)).Times(1)
+
+	ctrl.Call(recv, "Report", fmt.Errorf("read: %w", io.EOF))
+	ctrl.Call(recv, "Report", nil)
+	if len(rep.fatals) != 0 {
+		t.Fatalf("Matching calls reported %q", rep.fatals)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ctrl.Call(recv, "Report", errors.New("uh oh"))
+	}()
+	<-done
+	if len(rep.fatals) != 1 {
+		t.Fatalf("Unexpected call reported %d fatal errors; want 1", len(rep.fatals))
+	}
+	if got, want := rep.fatals[0], "got error uh oh; want nil"; !strings.Contains(got, want) {
+		t.Errorf("Unexpected call reported %q; want containing %q", got, want)
+	}
+	ctrl.Finish()
+}
@@ -0,0 +1,17 @@
+module github.com/arr4n/shed/testerr/grpcerr
+
+go 1.24.8
+
+replace github.com/arr4n/shed => ../..
+
+require (
+	github.com/arr4n/shed v0.0.0-00010101000000-000000000000
+	github.com/google/go-cmp v0.7.0
+	google.golang.org/grpc v1.80.0
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	golang.org/x/sys v0.40.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260120221211-b8f7ae30c516 // indirect
+)
@@ -0,0 +1,138 @@
+// Package grpcerr provides [testerr.Want] implementations for errors carrying
+// a gRPC [status.Status]. It is a separate module so that the testerr package
+// doesn't depend on gRPC.
+//
+// The Status of an error is that returned by [status.FromError], which also
+// searches wrapped errors. Errors that don't carry a Status but that are, or
+// wrap, [context.Canceled] or [context.DeadlineExceeded] are instead treated as
+// having the respective Status returned by [status.FromContextError], mirroring
+// how gRPC reports them to clients.
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/arr4n/shed/testerr"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// statusWant returns a [testerr.Want], described by `desc`, that passes the
+// Status of the `got` error to `diff`, which is only called if there is one.
+func statusWant(desc string, diff func(got error, st *status.Status) string) testerr.Want {
+	return testerr.DescribedFunc(desc, func(got error) string {
+		st, ok := statusOf(got)
+		if !ok {
+			return testerr.DiffMessage(got, "%s; no gRPC Status found in error of type %T", desc, got)
+		}
+		return diff(got, st)
+	})
+}
+
+// statusOf returns the Status of `err`; see the package documentation.
+func statusOf(err error) (*status.Status, bool) {
+	if st, ok := status.FromError(err); ok {
+		return st, true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err), true
+	}
+	return nil, false
+}
+
+// Code checks that the `got` error has a Status with the code. As a nil error
+// has an OK Status, `Code(codes.OK)` matches it.
+func Code(c codes.Code) testerr.Want {
+	desc := fmt.Sprintf("gRPC Status with code %v", c)
+	return statusWant(desc, func(got error, st *status.Status) string {
+		if st.Code() != c {
+			return testerr.DiffMessage(got, "%s; got code %v", desc, st.Code())
+		}
+		return ""
+	})
+}
+
+// CodeAndMessage checks that the `got` error has a Status with the code, and a
+// message matching `msgWant`. The message is checked, with [testerr.Diff], as
+// an error of which it is the string, so a nil `msgWant` never matches and
+// [Code] SHOULD be used instead.
+func CodeAndMessage(c codes.Code, msgWant testerr.Want) testerr.Want {
+	desc := fmt.Sprintf("gRPC Status with code %v and message %s", c, testerr.Describe(msgWant))
+	return statusWant(desc, func(got error, st *status.Status) string {
+		if st.Code() != c {
+			return testerr.DiffMessage(got, "%s; got code %v", desc, st.Code())
+		}
+		if d := testerr.Diff(errors.New(st.Message()), msgWant); d != "" {
+			return testerr.DiffMessage(got, "%s; message: %s", desc, d)
+		}
+		return ""
+	})
+}
+
+// Details checks that the `got` error has a Status with details such that every
+// one of the matchers returns an empty string for at least one of them.
+// Matchers follow the same semantics as the `match()` function passed to
+// [testerr.As], and [DetailEqual] provides a common one. As with [testerr.All],
+// Details() without any arguments always returns a diff.
+func Details(matchers ...func(proto.Message) string) testerr.Want {
+	desc := fmt.Sprintf("gRPC Status with details matching %d matcher(s)", len(matchers))
+	return statusWant(desc, func(got error, st *status.Status) string {
+		if len(matchers) == 0 {
+			return testerr.DiffMessage(got, "Details() of at least one matcher")
+		}
+
+		var details []proto.Message
+		for i, d := range st.Details() {
+			m, ok := d.(proto.Message)
+			if !ok {
+				return testerr.DiffMessage(got, "%s; detail [%d] not resolvable: %v", desc, i, d)
+			}
+			details = append(details, m)
+		}
+		if len(details) == 0 {
+			return testerr.DiffMessage(got, "%s; got no details", desc)
+		}
+
+		var failed []string
+		for i, match := range matchers {
+			var diffs []string
+			for _, d := range details {
+				diff := match(d)
+				if diff == "" {
+					diffs = nil
+					break
+				}
+				diffs = append(diffs, diff)
+			}
+			if len(diffs) > 0 {
+				failed = append(failed, fmt.Sprintf("\t[%d] %s", i, strings.ReplaceAll(strings.Join(diffs, "\n"), "\n", "\n\t\t")))
+			}
+		}
+		if len(failed) == 0 {
+			return ""
+		}
+		return testerr.DiffMessage(got, "%s; %d unmatched:\n%s", desc, len(failed), strings.Join(failed, "\n"))
+	})
+}
+
+// DetailEqual returns a matcher, for use with [Details], that checks a detail
+// for equality with `want`, according to [cmp.Diff] with [protocmp.Transform].
+// Details of a different message type are reported by their full name.
+func DetailEqual(want proto.Message) func(proto.Message) string {
+	wantName := want.ProtoReflect().Descriptor().FullName()
+	return func(got proto.Message) string {
+		if name := got.ProtoReflect().Descriptor().FullName(); name != wantName {
+			return fmt.Sprintf("got %s; want %s", name, wantName)
+		}
+		if d := cmp.Diff(want, got, protocmp.Transform()); d != "" {
+			return fmt.Sprintf("%s diff (-want +got):\n%s", wantName, d)
+		}
+		return ""
+	}
+}
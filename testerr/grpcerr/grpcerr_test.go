@@ -0,0 +1,215 @@
+package grpcerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arr4n/shed/testerr"
+	"github.com/arr4n/shed/testerr/grpcerr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func ExampleCode() {
+	errNotFound := status.Error(codes.NotFound, "no such user")
+
+	for _, err := range []error{
+		errNotFound,
+		fmt.Errorf("lookup: %w", errNotFound),
+		status.Error(codes.Internal, "oops"),
+		errors.New("plain"),
+		fmt.Errorf("call: %w", context.DeadlineExceeded),
+	} {
+		if diff := testerr.Diff(err, grpcerr.Code(codes.NotFound)); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// <empty>
+	// got error rpc error: code = Internal desc = oops; want gRPC Status with code NotFound; got code Internal
+	// got error plain; want gRPC Status with code NotFound; no gRPC Status found in error of type *errors.errorString
+	// got error call: context deadline exceeded; want gRPC Status with code NotFound; got code DeadlineExceeded
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		code     codes.Code
+		wantDiff bool
+	}{
+		{
+			name: "nil error is OK",
+			err:  nil,
+			code: codes.OK,
+		},
+		{
+			name:     "nil error isn't NotFound",
+			err:      nil,
+			code:     codes.NotFound,
+			wantDiff: true,
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("%w", status.Error(codes.PermissionDenied, "no")),
+			code: codes.PermissionDenied,
+		},
+		{
+			name: "context.Canceled",
+			err:  fmt.Errorf("stream: %w", context.Canceled),
+			code: codes.Canceled,
+		},
+		{
+			name: "context.DeadlineExceeded",
+			err:  context.DeadlineExceeded,
+			code: codes.DeadlineExceeded,
+		},
+		{
+			name:     "plain error",
+			err:      errors.New("unknown"),
+			code:     codes.Unknown,
+			wantDiff: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := testerr.Diff(tt.err, grpcerr.Code(tt.code))
+			if got := diff != ""; got != tt.wantDiff {
+				t.Errorf("Diff(%v, Code(%v)) got diff %q; want non-empty = %t", tt.err, tt.code, diff, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func TestCodeAndMessage(t *testing.T) {
+	st := status.New(codes.InvalidArgument, "field x: must be positive")
+
+	tests := []struct {
+		name string
+		err  error
+		want testerr.Want
+		// wantDiff is a substring of the expected diff, or empty if none
+		wantDiff string
+	}{
+		{
+			name: "match",
+			err:  st.Err(),
+			want: grpcerr.CodeAndMessage(codes.InvalidArgument, testerr.HasPrefix("field x:")),
+		},
+		{
+			name: "wrapped status message includes wrapping",
+			err:  fmt.Errorf("validate: %w", st.Err()),
+			want: grpcerr.CodeAndMessage(codes.InvalidArgument, testerr.MessageIs("validate: rpc error: code = InvalidArgument desc = field x: must be positive")),
+		},
+		{
+			name:     "code mismatch",
+			err:      st.Err(),
+			want:     grpcerr.CodeAndMessage(codes.NotFound, testerr.Contains("x")),
+			wantDiff: "; got code InvalidArgument",
+		},
+		{
+			name:     "message mismatch",
+			err:      st.Err(),
+			want:     grpcerr.CodeAndMessage(codes.InvalidArgument, testerr.Contains("field y")),
+			wantDiff: `; message: got error field x: must be positive; want containing substring "field y"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := testerr.Diff(tt.err, tt.want)
+			if tt.wantDiff == "" {
+				if diff != "" {
+					t.Errorf("Diff(%v, %s) %s", tt.err, testerr.Describe(tt.want), diff)
+				}
+				return
+			}
+			if !strings.Contains(diff, tt.wantDiff) {
+				t.Errorf("Diff(%v, %s) got %q; want containing %q", tt.err, testerr.Describe(tt.want), diff, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func TestDetails(t *testing.T) {
+	withDetails := func(details ...protoadapt.MessageV1) error {
+		t.Helper()
+		st := status.New(codes.FailedPrecondition, "not ready")
+		for _, d := range details {
+			var err error
+			if st, err = st.WithDetails(d); err != nil {
+				t.Fatalf("%T.WithDetails(%T) error %v", st, d, err)
+			}
+		}
+		return fmt.Errorf("wrapped: %w", st.Err())
+	}
+	retry := durationpb.New(3 * time.Second)
+	reason := wrapperspb.String("warming up")
+
+	tests := []struct {
+		name     string
+		err      error
+		matchers []func(proto.Message) string
+		// wantDiff are substrings of the expected diff, or empty if none
+		wantDiff []string
+	}{
+		{
+			name:     "all matched in any order",
+			err:      withDetails(retry, reason),
+			matchers: []func(proto.Message) string{grpcerr.DetailEqual(wrapperspb.String("warming up")), grpcerr.DetailEqual(durationpb.New(3 * time.Second))},
+		},
+		{
+			name:     "value mismatch",
+			err:      withDetails(retry, reason),
+			matchers: []func(proto.Message) string{grpcerr.DetailEqual(wrapperspb.String("cooling down"))},
+			wantDiff: []string{"1 unmatched", "[0]", "got google.protobuf.Duration; want google.protobuf.StringValue", "google.protobuf.StringValue diff (-want +got)", "cooling down"},
+		},
+		{
+			name:     "no details",
+			err:      withDetails(),
+			matchers: []func(proto.Message) string{grpcerr.DetailEqual(reason)},
+			wantDiff: []string{"got no details"},
+		},
+		{
+			name:     "no matchers",
+			err:      withDetails(reason),
+			wantDiff: []string{"Details() of at least one matcher"},
+		},
+		{
+			name:     "plain error",
+			err:      errors.New("plain"),
+			matchers: []func(proto.Message) string{grpcerr.DetailEqual(reason)},
+			wantDiff: []string{"no gRPC Status found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := testerr.Diff(tt.err, grpcerr.Details(tt.matchers...))
+			if len(tt.wantDiff) == 0 {
+				if diff != "" {
+					t.Errorf("Diff(%v, Details(…)) %s", tt.err, diff)
+				}
+				return
+			}
+			for _, want := range tt.wantDiff {
+				if !strings.Contains(diff, want) {
+					t.Errorf("Diff(%v, Details(…)) got %q; want containing %q", tt.err, diff, want)
+				}
+			}
+		})
+	}
+}
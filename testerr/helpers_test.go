@@ -0,0 +1,27 @@
+package testerr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// checkDiff checks that the diff of `err` against `want` is that of
+// [testerr.DiffMessage] with the expectation `wantDiff`, or empty if
+// `wantDiff` is, and that [testerr.Matches] agrees. As go-cmp randomly
+// interchanges spaces with non-breaking ones, the latter are replaced before
+// comparison.
+func checkDiff(t *testing.T, err error, want testerr.Want, wantDiff string) {
+	t.Helper()
+	var wantFull string
+	if wantDiff != "" {
+		wantFull = testerr.DiffMessage(err, "%s", wantDiff)
+	}
+	if diff := strings.ReplaceAll(testerr.Diff(err, want), "\u00a0", " "); diff != wantFull {
+		t.Errorf("Diff(%v, %s) got %q; want %q", err, testerr.Describe(want), diff, wantFull)
+	}
+	if got := testerr.Matches(err, want); got != (wantDiff == "") {
+		t.Errorf("Matches(%v, %s) got %t; want %t", err, testerr.Describe(want), got, !got)
+	}
+}
@@ -0,0 +1,306 @@
+package testerr
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// components returns the immediate children of a multi-error, i.e. one
+// implementing `Unwrap() []error` such as those returned by [errors.Join]. If
+// `got` isn't such an error, it returns a non-empty diff, prefixed by `desc`.
+func components(got error, desc string) ([]error, string) {
+	if got == nil {
+		return nil, DiffMessage(got, "%s", desc)
+	}
+	u, ok := got.(interface{ Unwrap() []error })
+	if !ok {
+		return nil, DiffMessage(got, "%s; got %T without Unwrap() []error", desc, got)
+	}
+	return u.Unwrap(), ""
+}
+
+// Joined checks that the `got` error has an `Unwrap() []error` method, as
+// returned by [errors.Join] or [fmt.Errorf] with multiple %w verbs, and that
+// its components match the `wants`, in order. Only immediate components are
+// considered, so their number MUST equal the number of `wants`.
+//
+// As a multi-error never has nil components, a nil [Want] would never be
+// satisfied and is therefore rejected with a diff.
+func Joined(wants ...Want) Want {
+	desc := fmt.Sprintf("joined error with components %s", describeAll(wants))
+
+	return &described{
+		desc: desc,
+		diff: func(got error) string {
+			for i, w := range wants {
+				if isNil(w) {
+					return DiffMessage(got, "Joined() of non-nil Wants; got nil at index %d", i)
+				}
+			}
+
+			errs, d := components(got, desc)
+			if d != "" {
+				return d
+			}
+			if len(errs) != len(wants) {
+				return DiffMessage(got, "%s; got %d components", desc, len(errs))
+			}
+
+			var failed []string
+			for i, w := range wants {
+				if d := w.ErrDiff(errs[i]); d != "" {
+					failed = append(failed, indexedDiff(i, d))
+				}
+			}
+			if len(failed) == 0 {
+				return ""
+			}
+			return DiffMessage(
+				got, "%s; %d component(s) mismatched:\n%s",
+				desc, len(failed), strings.Join(failed, "\n"),
+			)
+		},
+	}
+}
+
+// JoinedUnordered is equivalent to [Joined] except that the order of
+// components is ignored. It checks that there is a one-to-one assignment of
+// components to `wants` such that every component matches its assigned [Want].
+// Assignment is a maximum bipartite matching, not a greedy search, so a broad
+// [Want] (e.g. [Contains]) doesn't prevent a narrower one from being
+// satisfied. On failure, the diff reports the components and Wants that were
+// left unassigned by a maximum matching.
+func JoinedUnordered(wants ...Want) Want {
+	desc := fmt.Sprintf("joined error with components, in any order, %s", describeAll(wants))
+
+	match := func(got error) bool {
+		u, ok := got.(interface{ Unwrap() []error })
+		if !ok || slices.ContainsFunc(wants, isNil) {
+			return false
+		}
+		errs := u.Unwrap()
+		if len(errs) != len(wants) {
+			return false
+		}
+		if len(wants) > maxSmallMatching {
+			return joinedUnorderedSlow(errs, wants)
+		}
+		var m smallMatching
+		for i, err := range errs {
+			for j, w := range wants {
+				if Matches(err, w) {
+					m.edges[i] |= 1 << j
+				}
+			}
+		}
+		return m.perfect(len(errs))
+	}
+
+	return &described{
+		desc:  desc,
+		match: match,
+		diff: func(got error) string {
+			if match(got) {
+				return ""
+			}
+			for i, w := range wants {
+				if isNil(w) {
+					return DiffMessage(got, "JoinedUnordered() of non-nil Wants; got nil at index %d", i)
+				}
+			}
+
+			errs, d := components(got, desc)
+			if d != "" {
+				return d
+			}
+			if len(errs) != len(wants) {
+				return DiffMessage(got, "%s; got %d components", desc, len(errs))
+			}
+
+			matches := make([][]bool, len(errs))
+			for i, err := range errs {
+				matches[i] = make([]bool, len(wants))
+				for j, w := range wants {
+					matches[i][j] = Matches(err, w)
+				}
+			}
+			errToWant := maxBipartiteMatching(matches, len(wants))
+
+			assigned := make([]bool, len(wants))
+			var unmatchedErrs []string
+			for i, j := range errToWant {
+				if j == -1 {
+					unmatchedErrs = append(unmatchedErrs, fmt.Sprintf("[%d] %s", i, quoteMessage(errs[i])))
+					continue
+				}
+				assigned[j] = true
+			}
+			if len(unmatchedErrs) == 0 {
+				return ""
+			}
+			var unmatchedWants []string
+			for j, ok := range assigned {
+				if !ok {
+					unmatchedWants = append(unmatchedWants, fmt.Sprintf("[%d] %s", j, Describe(wants[j])))
+				}
+			}
+			return DiffMessage(
+				got, "%s; unmatched components: %s; unmatched Wants: %s",
+				desc, strings.Join(unmatchedErrs, ", "), strings.Join(unmatchedWants, ", "),
+			)
+		},
+	}
+}
+
+// joinedUnorderedSlow reports whether there is a one-to-one assignment of
+// `errs` to `wants`, as described by [JoinedUnordered], regardless of their
+// number.
+func joinedUnorderedSlow(errs []error, wants []Want) bool {
+	matches := make([][]bool, len(errs))
+	for i, err := range errs {
+		matches[i] = make([]bool, len(wants))
+		for j, w := range wants {
+			matches[i][j] = Matches(err, w)
+		}
+	}
+	return !slices.Contains(maxBipartiteMatching(matches, len(wants)), -1)
+}
+
+// maxSmallMatching is the maximum number of vertices on either side of a
+// [smallMatching].
+const maxSmallMatching = 64
+
+// smallMatching is an allocation-free equivalent of [maxBipartiteMatching] for
+// equal numbers of left- and right-hand vertices, of which there are at most
+// [maxSmallMatching]. The i-th bit of `edges[l]` denotes an edge between `l`
+// and right-hand vertex `i`.
+type smallMatching struct {
+	edges       [maxSmallMatching]uint64
+	rightToLeft [maxSmallMatching]int8 // offset by 1 so the zero value is unmatched
+}
+
+// perfect reports whether the first `n` vertices on each side have a perfect
+// matching.
+func (m *smallMatching) perfect(n int) bool {
+	for l := range n {
+		var seen uint64
+		if !m.augment(l, &seen) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *smallMatching) augment(l int, seen *uint64) bool {
+	for es := m.edges[l] &^ *seen; es != 0; es &= es - 1 {
+		r := bits.TrailingZeros64(es)
+		if *seen&(1<<r) != 0 {
+			continue
+		}
+		*seen |= 1 << r
+		if prev := int(m.rightToLeft[r]) - 1; prev == -1 || m.augment(prev, seen) {
+			m.rightToLeft[r] = int8(l + 1)
+			return true
+		}
+	}
+	return false
+}
+
+// maxBipartiteMatching returns a maximum matching between left- and
+// right-hand vertices, where `edges[l][r]` denotes an edge. The returned slice
+// maps each left-hand vertex to its matched right-hand vertex, or to -1 if
+// unmatched.
+func maxBipartiteMatching(edges [][]bool, numRight int) []int {
+	rightToLeft := make([]int, numRight)
+	for r := range rightToLeft {
+		rightToLeft[r] = -1
+	}
+
+	// Kuhn's algorithm: for each left-hand vertex, search for an augmenting
+	// path via depth-first search.
+	var augment func(l int, seen []bool) bool
+	augment = func(l int, seen []bool) bool {
+		for r, ok := range edges[l] {
+			if !ok || seen[r] {
+				continue
+			}
+			seen[r] = true
+			if rightToLeft[r] == -1 || augment(rightToLeft[r], seen) {
+				rightToLeft[r] = l
+				return true
+			}
+		}
+		return false
+	}
+	for l := range edges {
+		augment(l, make([]bool, numRight))
+	}
+
+	leftToRight := make([]int, len(edges))
+	for l := range leftToRight {
+		leftToRight[l] = -1
+	}
+	for r, l := range rightToLeft {
+		if l != -1 {
+			leftToRight[l] = r
+		}
+	}
+	return leftToRight
+}
+
+// JoinedLen checks that the `got` error has an `Unwrap() []error` method, as
+// returned by [errors.Join], that returns exactly `n` immediate components.
+// Components aren't recursed into.
+//
+// A nil error never matches, even if `n == 0`, so as to distinguish between
+// an empty multi-error and no error at all. Note that [errors.Join] returns
+// nil, not an empty multi-error, if all of its arguments are nil; use a nil
+// [Want] to check for this.
+func JoinedLen(n int) Want {
+	return joinedLen(fmt.Sprintf("joined error with %d component(s)", n), func(c int) bool { return c == n })
+}
+
+// JoinedLenAtLeast is equivalent to [JoinedLen] except that it allows more
+// than `n` components.
+func JoinedLenAtLeast(n int) Want {
+	return joinedLen(fmt.Sprintf("joined error with at least %d component(s)", n), func(c int) bool { return c >= n })
+}
+
+// JoinedLenAtMost is equivalent to [JoinedLen] except that it allows fewer
+// than `n` components.
+func JoinedLenAtMost(n int) Want {
+	return joinedLen(fmt.Sprintf("joined error with at most %d component(s)", n), func(c int) bool { return c <= n })
+}
+
+// maxListedComponents is the maximum number of components for which
+// [JoinedLen] and its variants include messages in their diffs, and of leaves
+// listed by [AnywhereInTree].
+const maxListedComponents = 5
+
+func joinedLen(desc string, ok func(int) bool) Want {
+	return &described{
+		desc: desc,
+		diff: func(got error) string {
+			if got == nil {
+				return DiffMessage(got, "%s; got nil error, not an empty join", desc)
+			}
+			errs, d := components(got, desc)
+			if d != "" {
+				return d
+			}
+			if ok(len(errs)) {
+				return ""
+			}
+			if len(errs) > maxListedComponents {
+				return DiffMessage(got, "%s; got %d components", desc, len(errs))
+			}
+			msgs := make([]string, len(errs))
+			for i, e := range errs {
+				msgs[i] = quoteMessage(e)
+			}
+			return DiffMessage(got, "%s; got %d components [%s]", desc, len(errs), strings.Join(msgs, " "))
+		},
+	}
+}
@@ -0,0 +1,162 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// printDiff prints the diff of `err` against `want`, with the <empty>
+// placeholder for a match. Newlines in the error's message are escaped to keep
+// the output readable while those in the rest of the diff are retained.
+func printDiff(err error, want testerr.Want) {
+	diff := testerr.Diff(err, want)
+	if diff == "" {
+		fmt.Println("<empty>")
+		return
+	}
+	if err != nil {
+		msg := err.Error()
+		diff = strings.ReplaceAll(diff, msg, strings.ReplaceAll(msg, "\n", `\n`))
+	}
+	fmt.Println(diff)
+}
+
+func ExampleJoined() {
+	errUhOh := errors.New("uh oh")
+	err := errors.Join(errUhOh, fmt.Errorf("wrapped: %w", io.EOF))
+
+	for _, want := range []testerr.Want{
+		testerr.Joined(testerr.Is(errUhOh), testerr.Is(io.EOF)),
+		testerr.Joined(testerr.Is(io.EOF), testerr.Is(errUhOh)),
+		testerr.Joined(testerr.Is(errUhOh)),
+		testerr.Joined(testerr.Is(errUhOh), nil),
+	} {
+		printDiff(err, want)
+	}
+
+	printDiff(errUhOh, testerr.Joined(testerr.Is(errUhOh)))
+	printDiff(nil, testerr.Joined())
+
+	// Output:
+	// <empty>
+	// got error uh oh\nwrapped: EOF; want joined error with components [error that Is() EOF; error that Is() uh oh]; 2 component(s) mismatched:
+	// 	[0] got error uh oh; want error that Is() EOF
+	// 	[1] got error wrapped: EOF; want error that Is() uh oh
+	// got error uh oh\nwrapped: EOF; want joined error with components [error that Is() uh oh]; got 2 components
+	// got error uh oh\nwrapped: EOF; want Joined() of non-nil Wants; got nil at index 1
+	// got error uh oh; want joined error with components [error that Is() uh oh]; got *errors.errorString without Unwrap() []error
+	// got error <nil>; want joined error with components []
+}
+
+func ExampleJoinedUnordered() {
+	errX := errors.New("x")
+	errY := errors.New("y")
+
+	// A greedy assignment of components to Wants would assign errX to the
+	// broader Contains("x"), leaving Is(errX) unable to match "x again".
+	notGreedy := testerr.JoinedUnordered(testerr.Contains("x"), testerr.Is(errX))
+
+	for _, tt := range []struct {
+		err  error
+		want testerr.Want
+	}{
+		{errors.Join(errX, errors.New("x again")), notGreedy},
+		{errors.Join(errors.New("x again"), errX), notGreedy},
+		{errors.Join(errY, errX), testerr.JoinedUnordered(testerr.Is(errX), testerr.Is(errY))},
+		{errors.Join(errY, errY), testerr.JoinedUnordered(testerr.Is(errX), testerr.Is(errY))},
+		{errors.Join(errX, errY), testerr.JoinedUnordered(testerr.Is(errX))},
+	} {
+		printDiff(tt.err, tt.want)
+	}
+
+	// Output:
+	// <empty>
+	// <empty>
+	// <empty>
+	// got error y\ny; want joined error with components, in any order, [error that Is() x; error that Is() y]; unmatched components: [1] "y"; unmatched Wants: [0] error that Is() x
+	// got error x\ny; want joined error with components, in any order, [error that Is() x]; got 2 components
+}
+
+func TestJoinedUnorderedManyWants(t *testing.T) {
+	// Beyond 64 Wants, matching no longer uses a bitset so the sizes straddle
+	// the boundary.
+	for _, n := range []int{64, 65, 100} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			errs := make([]error, n)
+			wants := make([]testerr.Want, n)
+			for i := range n {
+				errs[i] = fmt.Errorf("err %d", i)
+				wants[i] = testerr.Is(errs[i])
+			}
+			// As in the JoinedUnordered example, a greedy assignment of the
+			// last two components would give the penultimate one to the broader
+			// Want, leaving the last component unmatched.
+			a, b := errs[n-2], errs[n-1]
+			wants[n-2] = testerr.Any(testerr.Is(a), testerr.Is(b))
+			wants[n-1] = testerr.Is(a)
+			want := testerr.JoinedUnordered(wants...)
+
+			if diff := testerr.Diff(errors.Join(errs...), want); diff != "" {
+				t.Errorf("Diff(errors.Join(<%d errors>), JoinedUnordered(…)) got %q; want empty", n, diff)
+			}
+			if !testerr.Matches(errors.Join(errs...), want) {
+				t.Errorf("Matches(errors.Join(<%d errors>), JoinedUnordered(…)) got false; want true", n)
+			}
+
+			// Only the broader Want can match the last component so the
+			// narrower one is necessarily left unmatched.
+			errs[n-2] = io.EOF
+			got := errors.Join(errs...)
+			wantSuffix := fmt.Sprintf(`; unmatched components: [%d] "EOF"; unmatched Wants: [%d] %s`, n-2, n-1, testerr.Describe(wants[n-1]))
+			if diff := testerr.Diff(got, want); !strings.HasSuffix(diff, wantSuffix) {
+				t.Errorf("Diff(errors.Join(<%d errors, one unexpected>), JoinedUnordered(…)) got %q; want suffix %q", n, diff, wantSuffix)
+			}
+			if testerr.Matches(got, want) {
+				t.Errorf("Matches(errors.Join(<%d errors, one unexpected>), JoinedUnordered(…)) got true; want false", n)
+			}
+		})
+	}
+}
+
+// emptyJoin is a multi-error without any components, which can't be created
+// with [errors.Join].
+type emptyJoin struct{}
+
+func (emptyJoin) Error() string   { return "empty" }
+func (emptyJoin) Unwrap() []error { return nil }
+
+func ExampleJoinedLen() {
+	a, b, c := errors.New("a"), errors.New("b"), errors.New("c")
+	err := errors.Join(a, fmt.Errorf("%w + %w", b, c))
+
+	for _, tt := range []struct {
+		err  error
+		want testerr.Want
+	}{
+		{err, testerr.JoinedLen(2)}, // doesn't recurse into "b + c"
+		{err, testerr.JoinedLen(3)},
+		{err, testerr.JoinedLenAtLeast(1)},
+		{err, testerr.JoinedLenAtMost(1)},
+		{errors.Join(a, b, c, a, b, c), testerr.JoinedLen(2)},
+		{a, testerr.JoinedLen(1)},
+		{emptyJoin{}, testerr.JoinedLen(0)},
+		{errors.Join(nil, nil), testerr.JoinedLen(0)},
+	} {
+		printDiff(tt.err, tt.want)
+	}
+
+	// Output:
+	// <empty>
+	// got error a\nb + c; want joined error with 3 component(s); got 2 components ["a" "b + c"]
+	// <empty>
+	// got error a\nb + c; want joined error with at most 1 component(s); got 2 components ["a" "b + c"]
+	// got error a\nb\nc\na\nb\nc; want joined error with 2 component(s); got 6 components
+	// got error a; want joined error with 1 component(s); got *errors.errorString without Unwrap() []error
+	// <empty>
+	// got error <nil>; want joined error with 0 component(s); got nil error, not an empty join
+}
@@ -0,0 +1,114 @@
+// Package jsonerr provides [testerr.Want] implementations for errors returned
+// by the [encoding/json] package. All of them search the `got` error's tree so
+// are unaffected by wrapping.
+package jsonerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// Syntax checks that the `got` error unwraps, via [errors.As], to a
+// [json.SyntaxError], the `Offset` of which is passed to `offsetWant`. The
+// function follows the same semantics as the `match()` function passed to
+// [testerr.As], and a nil `offsetWant` accepts all offsets.
+func Syntax(offsetWant func(int64) (expected string)) testerr.Want {
+	return testerr.As(func(got *json.SyntaxError) string {
+		if offsetWant == nil {
+			return ""
+		}
+		if d := offsetWant(got.Offset); d != "" {
+			return fmt.Sprintf("*json.SyntaxError with Offset %s; got Offset %d", d, got.Offset)
+		}
+		return ""
+	})
+}
+
+// Offset returns a function, for use with [Syntax], that accepts only `want`.
+func Offset(want int64) func(int64) string {
+	return func(got int64) string {
+		if got == want {
+			return ""
+		}
+		return strconv.FormatInt(want, 10)
+	}
+}
+
+// Type checks that the `got` error unwraps, via [errors.As], to a
+// [json.UnmarshalTypeError] with the `Field`, which is the full dotted path
+// from the root value (e.g. "user.age"), and with a `Type` the
+// [reflect.Type.String] of which is `wantType` (e.g. "int" or "[]string"). The
+// diff names every mismatched attribute.
+func Type(field, wantType string) testerr.Want {
+	return testerr.As(func(got *json.UnmarshalTypeError) string {
+		var failed []string
+		if got.Field != field {
+			failed = append(failed, fmt.Sprintf("got Struct field %q; want %q", got.Field, field))
+		}
+		if t := typeString(got); t != wantType {
+			failed = append(failed, fmt.Sprintf("got Go type %s; want %s", t, wantType))
+		}
+		if len(failed) == 0 {
+			return ""
+		}
+		return fmt.Sprintf(
+			"*json.UnmarshalTypeError with Struct field %q and Go type %s, from JSON %s; %s",
+			field, wantType, got.Value, strings.Join(failed, "; "),
+		)
+	})
+}
+
+func typeString(e *json.UnmarshalTypeError) string {
+	if e.Type == nil {
+		return "<nil>"
+	}
+	return e.Type.String()
+}
+
+// unknownFieldPrefix is the message prefix of the error returned when a
+// [json.Decoder] with `DisallowUnknownFields()` encounters an unknown field.
+// The error has no dedicated type so its message is parsed instead.
+const unknownFieldPrefix = "json: unknown field "
+
+// Unknown checks that a node in the `got` error's tree is the error that a
+// [json.Decoder] returns for an unknown `field` after a call to
+// [json.Decoder.DisallowUnknownFields]. As the error has no dedicated type,
+// nodes are identified by their messages.
+func Unknown(field string) testerr.Want {
+	desc := fmt.Sprintf("unknown-field error for %q", field)
+	return testerr.DescribedFunc(desc, func(got error) string {
+		fields := unknownFields(got)
+		for _, f := range fields {
+			if f == field {
+				return ""
+			}
+		}
+		if len(fields) == 0 {
+			return testerr.DiffMessage(got, "%s; found no unknown-field errors in tree", desc)
+		}
+		return testerr.DiffMessage(got, "%s; got unknown field(s) %q", desc, fields)
+	})
+}
+
+// unknownFields returns the names of the unknown fields reported by nodes in
+// the error tree.
+func unknownFields(err error) []string {
+	var fields []string
+	testerr.Walk(err, func(node error) bool {
+		msg, ok := testerr.Message(node)
+		if !ok {
+			return true
+		}
+		if q, ok := strings.CutPrefix(msg, unknownFieldPrefix); ok {
+			if f, err := strconv.Unquote(q); err == nil {
+				fields = append(fields, f)
+			}
+		}
+		return true
+	})
+	return fields
+}
@@ -0,0 +1,149 @@
+package jsonerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+	"github.com/arr4n/shed/testerr/jsonerr"
+)
+
+type user struct {
+	ID  int `json:"id"`
+	Age int `json:"age"`
+}
+
+type request struct {
+	User user     `json:"user"`
+	Tags []string `json:"tags"`
+}
+
+// decode mirrors a typical helper that wraps [json.Decoder] errors.
+func decode(data string, disallowUnknown bool) error {
+	d := json.NewDecoder(strings.NewReader(data))
+	if disallowUnknown {
+		d.DisallowUnknownFields()
+	}
+	var r request
+	if err := d.Decode(&r); err != nil {
+		return fmt.Errorf("decoding request: %w", err)
+	}
+	return nil
+}
+
+func TestJSONErr(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		want     testerr.Want
+		wantDiff string // following "want"; empty if no diff is expected
+	}{
+		{
+			name: "Syntax() with Offset()",
+			err:  json.Unmarshal([]byte(`{"user": }`), new(request)),
+			want: jsonerr.Syntax(jsonerr.Offset(10)),
+		},
+		{
+			name: "Syntax() wrapped with nil offset check",
+			err:  decode(`{"user": {"id": 1,}}`, false),
+			want: jsonerr.Syntax(nil),
+		},
+		{
+			name:     "Syntax() Offset() mismatch",
+			err:      json.Unmarshal([]byte(`{"user": }`), new(request)),
+			want:     jsonerr.Syntax(jsonerr.Offset(3)),
+			wantDiff: "*json.SyntaxError with Offset 3; got Offset 10",
+		},
+		{
+			name:     "Syntax() of type error",
+			err:      json.Unmarshal([]byte(`{"tags": 1}`), new(request)),
+			want:     jsonerr.Syntax(nil),
+			wantDiff: "error tree containing type *json.SyntaxError; found types [*json.UnmarshalTypeError]",
+		},
+		{
+			name: "Type() nested field",
+			err:  decode(`{"user": {"age": "old"}}`, false),
+			want: jsonerr.Type("user.age", "int"),
+		},
+		{
+			name: "Type() of slice",
+			err:  json.Unmarshal([]byte(`{"tags": 1}`), new(request)),
+			want: jsonerr.Type("tags", "[]string"),
+		},
+		{
+			name:     "Type() field mismatch",
+			err:      decode(`{"user": {"age": "old"}}`, false),
+			want:     jsonerr.Type("user.id", "int"),
+			wantDiff: `*json.UnmarshalTypeError with Struct field "user.id" and Go type int, from JSON string; got Struct field "user.age"; want "user.id"`,
+		},
+		{
+			name:     "Type() both attributes mismatched",
+			err:      decode(`{"user": {"age": "old"}}`, false),
+			want:     jsonerr.Type("tags", "[]string"),
+			wantDiff: `*json.UnmarshalTypeError with Struct field "tags" and Go type []string, from JSON string; got Struct field "user.age"; want "tags"; got Go type int; want []string`,
+		},
+		{
+			name: "Unknown() wrapped",
+			err:  decode(`{"user": {"id": 1, "name": "x"}}`, true),
+			want: jsonerr.Unknown("name"),
+		},
+		{
+			name:     "Unknown() field mismatch",
+			err:      decode(`{"nickname": "x"}`, true),
+			want:     jsonerr.Unknown("name"),
+			wantDiff: `unknown-field error for "name"; got unknown field(s) ["nickname"]`,
+		},
+		{
+			name:     "Unknown() without unknown fields",
+			err:      decode(`{"nickname": "x"}`, false),
+			want:     jsonerr.Unknown("nickname"),
+			wantDiff: `unknown-field error for "nickname"; found no unknown-field errors in tree`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want string
+			if tt.wantDiff != "" {
+				want = testerr.DiffMessage(tt.err, "%s", tt.wantDiff)
+			}
+			if diff := testerr.Diff(tt.err, tt.want); diff != want {
+				t.Errorf("Diff(%v, %s) got %q; want %q", tt.err, testerr.Describe(tt.want), diff, want)
+			}
+		})
+	}
+}
+
+// cyclicError unwraps to itself.
+type cyclicError struct{}
+
+func (e *cyclicError) Error() string { return "cyclic" }
+func (e *cyclicError) Unwrap() error { return e }
+
+func TestUnknownCycle(t *testing.T) {
+	err := fmt.Errorf("%w %w", &cyclicError{}, decode(`{"x": 0}`, true))
+	if diff := testerr.Diff(err, jsonerr.Unknown("x")); diff != "" {
+		t.Errorf("Diff(<cyclic tree>, Unknown(\"x\")) %s", diff)
+	}
+}
+
+// goexitError calls runtime.Goexit when its message is requested, as would
+// t.FailNow().
+type goexitError struct{}
+
+func (goexitError) Error() string {
+	runtime.Goexit()
+	return "unreachable"
+}
+
+func TestUnknownWithGoexitingNode(t *testing.T) {
+	// Unlike fmt.Errorf(), errors.Join() doesn't call Error() on construction.
+	err := errors.Join(goexitError{}, decode(`{"x": 0}`, true))
+	if diff := testerr.Diff(err, jsonerr.Unknown("x")); diff != "" {
+		t.Errorf("Diff(<tree with Goexit-ing node>, Unknown(\"x\")) %s", diff)
+	}
+}
@@ -0,0 +1,156 @@
+package testerr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// JSONMessage checks that the `got` error's string contains a JSON document
+// equal to `want`, as compared by [cmp.Diff] with the options. The document is
+// either the entire message or, failing that, the first JSON object or array in
+// it, so a message such as `request failed: {"code": "quota_exceeded"}` is
+// supported. Key order and whitespace are therefore irrelevant.
+//
+// Both the document and `want`, the latter after a round trip through
+// [json.Marshal], are decoded into `any` values with numbers as [json.Number],
+// which are compared exactly by value so that, for example, 1, 1.0 and 1e0 are
+// equal but large integers don't lose precision. A `want` that can't be
+// marshalled results in a diff.
+//
+// On mismatch, the diff reports the `cmp.Diff` or, if no document could be
+// parsed, the text from which extraction was attempted and the parse error.
+func JSONMessage(want any, opts ...cmp.Option) Want {
+	return jsonMessage("JSON message equal to", want, false, opts)
+}
+
+// JSONMessageSubset is equivalent to [JSONMessage] except that only the keys
+// present in objects in `want` are compared, recursively, so a few fields of a
+// document can be checked without specifying the rest; e.g. a `want` of
+// `map[string]any{"code": "quota_exceeded"}`. Arrays are compared element-wise
+// and MUST be of equal length, with each element itself compared as a subset.
+func JSONMessageSubset(want any, opts ...cmp.Option) Want {
+	return jsonMessage("JSON message with subset", want, true, opts)
+}
+
+func jsonMessage(prefix string, want any, subset bool, opts []cmp.Option) Want {
+	wantJSON, marshalErr := json.Marshal(want)
+	desc := fmt.Sprintf("%s %s", prefix, wantJSON)
+	if marshalErr != nil {
+		desc = fmt.Sprintf("%s %+v", prefix, want)
+	}
+
+	var normWant any
+	if marshalErr == nil {
+		// Errors are impossible as the output of Marshal is valid JSON.
+		normWant, _ = decodeJSON(wantJSON)
+	}
+	opts = append([]cmp.Option{cmp.Comparer(jsonNumbersEqual)}, opts...)
+
+	return &described{
+		desc: desc,
+		diff: func(got error) string {
+			if marshalErr != nil {
+				return DiffMessage(got, "%s but marshalling failed: %v", desc, marshalErr)
+			}
+			if got == nil {
+				return DiffMessage(got, "%s", desc)
+			}
+
+			msg, ok := message(got)
+			if !ok {
+				return DiffMessage(got, "%s", desc)
+			}
+			doc, text, err := extractJSON(msg)
+			if err != nil {
+				return DiffMessage(got, "%s; failed to parse JSON from %q: %v", desc, text, err)
+			}
+			if subset {
+				doc = pruneJSON(doc, normWant)
+			}
+			if d := cmpDiff(normWant, doc, opts...); d != "" {
+				return DiffMessage(got, "%s; %s", desc, d)
+			}
+			return ""
+		},
+	}
+}
+
+// extractJSON decodes the JSON document in `msg`; see [JSONMessage]. It also
+// returns the text from which the document was decoded, including on error.
+func extractJSON(msg string) (_ any, text string, _ error) {
+	trimmed := strings.TrimSpace(msg)
+	if json.Valid([]byte(trimmed)) {
+		v, err := decodeJSON([]byte(trimmed))
+		return v, trimmed, err
+	}
+
+	i := strings.IndexAny(msg, "{[")
+	if i == -1 {
+		return nil, msg, fmt.Errorf("no JSON object or array found")
+	}
+	text = msg[i:]
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, text, err
+	}
+	return v, text[:dec.InputOffset()], nil
+}
+
+func decodeJSON(buf []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.UseNumber()
+	var v any
+	err := dec.Decode(&v)
+	return v, err
+}
+
+// jsonNumbersEqual reports whether the numbers are equal by value, without loss
+// of precision.
+func jsonNumbersEqual(a, b json.Number) bool {
+	if a == b {
+		return true
+	}
+	x, okX := new(big.Rat).SetString(string(a))
+	y, okY := new(big.Rat).SetString(string(b))
+	return okX && okY && x.Cmp(y) == 0
+}
+
+// pruneJSON returns `got` with all object keys that aren't present in the
+// respective object of `want` removed, recursively. Mismatched types, and
+// arrays of different lengths, are returned unchanged so they are reported in
+// full by [cmp.Diff].
+func pruneJSON(got, want any) any {
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			return got
+		}
+		pruned := make(map[string]any, len(w))
+		for k, wv := range w {
+			if gv, ok := g[k]; ok {
+				pruned[k] = pruneJSON(gv, wv)
+			}
+		}
+		return pruned
+	case []any:
+		g, ok := got.([]any)
+		if !ok || len(g) != len(w) {
+			return got
+		}
+		pruned := make([]any, len(g))
+		for i := range g {
+			pruned[i] = pruneJSON(g[i], w[i])
+		}
+		return pruned
+	default:
+		return got
+	}
+}
@@ -0,0 +1,169 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func ExampleJSONMessage() {
+	err := errors.New(`server: {"detail": {"used": 100, "limit": 1e2}, "code": "quota_exceeded"}`)
+
+	// The format of [cmp.Diff] output is deliberately unstable so only
+	// matches are demonstrated.
+	for _, want := range []testerr.Want{
+		testerr.JSONMessage(map[string]any{
+			"code":   "quota_exceeded",
+			"detail": map[string]int{"limit": 100, "used": 100},
+		}),
+		testerr.JSONMessageSubset(map[string]any{"code": "quota_exceeded"}),
+	} {
+		if diff := testerr.Diff(err, want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// <empty>
+}
+
+func TestJSONMessage(t *testing.T) {
+	type detail struct {
+		Limit int64   `json:"limit"`
+		Ratio float64 `json:"ratio"`
+	}
+	type payload struct {
+		Code   string   `json:"code"`
+		Detail detail   `json:"detail"`
+		Tags   []string `json:"tags"`
+	}
+
+	const doc = `{"tags": ["a", "b"], "code": "quota_exceeded", "detail": {"ratio": 0.5, "limit": 9007199254740993}}`
+	full := payload{
+		Code:   "quota_exceeded",
+		Detail: detail{Limit: 9007199254740993, Ratio: 0.5},
+		Tags:   []string{"a", "b"},
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		want     testerr.Want
+		wantDiff string // following "want"; empty if no diff is expected
+	}{
+		{
+			name: "whole message with struct",
+			err:  errors.New(doc),
+			want: testerr.JSONMessage(full),
+		},
+		{
+			name: "whitespace and key order",
+			err:  errors.New("\n" + strings.ReplaceAll(doc, " ", "\t") + "\n"),
+			want: testerr.JSONMessage(full),
+		},
+		{
+			name: "embedded object followed by text",
+			err:  fmt.Errorf("POST /v1: %w", errors.New(doc+" (retryable)")),
+			want: testerr.JSONMessage(full),
+		},
+		{
+			name: "embedded array",
+			err:  errors.New(`errors: [1, {"x": null}] occurred`),
+			want: testerr.JSONMessage([]any{1, map[string]any{"x": nil}}),
+		},
+		{
+			name: "numbers equal by value",
+			err:  errors.New(`{"n": 1.0, "m": 2e2, "big": 9007199254740993}`),
+			want: testerr.JSONMessage(map[string]any{"n": 1, "m": 200, "big": uint64(9007199254740993)}),
+		},
+		{
+			name:     "large integers compared without loss of precision",
+			err:      errors.New(`{"big": 9007199254740993}`),
+			want:     testerr.JSONMessage(map[string]any{"big": uint64(9007199254740992)}),
+			wantDiff: "JSON message equal to {\"big\":9007199254740992}; diff (-want +got):\n  map[string]any{\n- \t\"big\": s\"9007199254740992\",\n+ \t\"big\": s\"9007199254740993\",\n  }\n",
+		},
+		{
+			name:     "nested mismatch",
+			err:      errors.New(doc),
+			want:     testerr.JSONMessage(payload{Code: "quota_exceeded", Detail: detail{Limit: 1, Ratio: 0.5}, Tags: []string{"a", "b"}}),
+			wantDiff: "JSON message equal to {\"code\":\"quota_exceeded\",\"detail\":{\"limit\":1,\"ratio\":0.5},\"tags\":[\"a\",\"b\"]}; diff (-want +got):\n  map[string]any{\n  \t\"code\": string(\"quota_exceeded\"),\n  \t\"detail\": map[string]any{\n- \t\t\"limit\": s\"1\",\n+ \t\t\"limit\": s\"9007199254740993\",\n  \t\t\"ratio\": s\"0.5\",\n  \t},\n  \t\"tags\": []any{string(\"a\"), string(\"b\")},\n  }\n",
+		},
+		{
+			name:     "extra field without subset",
+			err:      errors.New(doc),
+			want:     testerr.JSONMessage(map[string]any{"code": "quota_exceeded"}),
+			wantDiff: "JSON message equal to {\"code\":\"quota_exceeded\"}; diff (-want +got):\n  map[string]any{\n  \t\"code\":   string(\"quota_exceeded\"),\n+ \t\"detail\": map[string]any{\"limit\": s\"9007199254740993\", \"ratio\": s\"0.5\"},\n+ \t\"tags\":   []any{string(\"a\"), string(\"b\")},\n  }\n",
+		},
+		{
+			name: "subset of nested fields",
+			err:  errors.New("quota: " + doc),
+			want: testerr.JSONMessageSubset(map[string]any{
+				"detail": map[string]any{"limit": uint64(9007199254740993)},
+				"tags":   []string{"a", "b"},
+			}),
+		},
+		{
+			name:     "subset mismatch",
+			err:      errors.New(doc),
+			want:     testerr.JSONMessageSubset(map[string]any{"code": "rate_limited"}),
+			wantDiff: "JSON message with subset {\"code\":\"rate_limited\"}; diff (-want +got):\n  map[string]any{\n- \t\"code\": string(\"rate_limited\"),\n+ \t\"code\": string(\"quota_exceeded\"),\n  }\n",
+		},
+		{
+			name:     "subset missing key",
+			err:      errors.New(doc),
+			want:     testerr.JSONMessageSubset(map[string]any{"retry_after": 5}),
+			wantDiff: "JSON message with subset {\"retry_after\":5}; diff (-want +got):\n  map[string]any{\n- \t\"retry_after\": s\"5\",\n  }\n",
+		},
+		{
+			name:     "subset array length mismatch",
+			err:      errors.New(doc),
+			want:     testerr.JSONMessageSubset(map[string]any{"tags": []string{"a"}}),
+			wantDiff: "JSON message with subset {\"tags\":[\"a\"]}; diff (-want +got):\n  map[string]any{\n  \t\"tags\": []any{\n  \t\tstring(\"a\"),\n+ \t\tstring(\"b\"),\n  \t},\n  }\n",
+		},
+		{
+			name: "cmp options",
+			err:  errors.New(`{"tags": ["b", "a"]}`),
+			want: testerr.JSONMessage(
+				map[string]any{"tags": []string{"a", "b"}},
+				cmpopts.SortSlices(func(a, b any) bool { return a.(string) < b.(string) }),
+			),
+		},
+		{
+			name:     "invalid JSON",
+			err:      errors.New(`bad: {"code": quota_exceeded}`),
+			want:     testerr.JSONMessage(map[string]any{}),
+			wantDiff: "JSON message equal to {}; failed to parse JSON from \"{\\\"code\\\": quota_exceeded}\": invalid character 'q' looking for beginning of value",
+		},
+		{
+			name:     "no JSON",
+			err:      errors.New("plain"),
+			want:     testerr.JSONMessage(map[string]any{}),
+			wantDiff: `JSON message equal to {}; failed to parse JSON from "plain": no JSON object or array found`,
+		},
+		{
+			name:     "nil error",
+			want:     testerr.JSONMessage(map[string]any{}),
+			wantDiff: "JSON message equal to {}",
+		},
+		{
+			name:     "unmarshallable want",
+			err:      errors.New("{}"),
+			want:     testerr.JSONMessage(math.Inf(1)),
+			wantDiff: "JSON message equal to +Inf but marshalling failed: json: unsupported value: +Inf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkDiff(t, tt.err, tt.want, tt.wantDiff)
+		})
+	}
+}
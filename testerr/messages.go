@@ -0,0 +1,406 @@
+package testerr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MatchesRegexp checks that the `got` error's string matches the regular
+// expression `pattern`, which is compiled at most once, the first time that the
+// returned [Want] is used. An invalid pattern results in a diff rather than a
+// panic. As with [Contains], a nil error never matches.
+func MatchesRegexp(pattern string) Want {
+	compile := sync.OnceValues(func() (*regexp.Regexp, error) {
+		return regexp.Compile(pattern)
+	})
+	desc := fmt.Sprintf("message matching regexp %q", pattern)
+
+	return &described{
+		desc: desc,
+		match: func(got error) bool {
+			re, err := compile()
+			return err == nil && messageMatches(got, re.MatchString)
+		},
+		diff: func(got error) string {
+			re, err := compile()
+			if err != nil {
+				return DiffMessage(got, "%s but pattern failed to compile: %v", desc, err)
+			}
+			return regexpDiff(got, re, desc)
+		},
+	}
+}
+
+// MatchesCompiledRegexp is equivalent to [MatchesRegexp] except that it
+// accepts an already-compiled regular expression. A nil `re` always results in
+// a diff.
+func MatchesCompiledRegexp(re *regexp.Regexp) Want {
+	if re == nil {
+		const desc = "message matching regexp"
+		return &described{
+			desc: desc,
+			diff: func(got error) string {
+				return DiffMessage(got, "%s but MatchesCompiledRegexp() regexp is nil", desc)
+			},
+		}
+	}
+	desc := fmt.Sprintf("message matching regexp %q", re)
+	return &described{
+		desc: desc,
+		match: func(got error) bool {
+			return messageMatches(got, re.MatchString)
+		},
+		diff: func(got error) string {
+			return regexpDiff(got, re, desc)
+		},
+	}
+}
+
+func regexpDiff(got error, re *regexp.Regexp, desc string) string {
+	if got == nil {
+		return DiffMessage(got, "%s", desc)
+	}
+	msg, ok := message(got)
+	if !ok {
+		return DiffMessage(got, "%s", desc)
+	}
+	if re.MatchString(msg) {
+		return ""
+	}
+	return DiffMessage(got, "%s; message %q does not match", desc, msg)
+}
+
+// HasPrefix checks that the `got` error's string begins with `prefix`. As with
+// [Contains], a nil error never matches.
+func HasPrefix(prefix string) Want {
+	return messagePredicate(
+		fmt.Sprintf("message with prefix %q", prefix),
+		func(msg string) bool { return strings.HasPrefix(msg, prefix) },
+	)
+}
+
+// HasSuffix checks that the `got` error's string ends with `suffix`. As with
+// [Contains], a nil error never matches.
+func HasSuffix(suffix string) Want {
+	return messagePredicate(
+		fmt.Sprintf("message with suffix %q", suffix),
+		func(msg string) bool { return strings.HasSuffix(msg, suffix) },
+	)
+}
+
+// ContainsAll checks that the `got` error's string contains every one of the
+// substrings, reporting those that are missing. As with [All], it rejects an
+// empty list of substrings, and as with [Contains], a nil error never matches.
+func ContainsAll(substrs ...string) Want {
+	desc := fmt.Sprintf("containing all substrings %q", substrs)
+	return &described{
+		desc: desc,
+		diff: func(got error) string {
+			if len(substrs) == 0 {
+				return DiffMessage(got, "ContainsAll() of at least one substring")
+			}
+			if got == nil {
+				return DiffMessage(got, "%s", desc)
+			}
+
+			msg, ok := message(got)
+			if !ok {
+				return DiffMessage(got, "%s", desc)
+			}
+			var missing []string
+			for _, s := range substrs {
+				if !strings.Contains(msg, s) {
+					missing = append(missing, s)
+				}
+			}
+			if len(missing) == 0 {
+				return ""
+			}
+			return DiffMessage(got, "%s; missing %q", desc, missing)
+		},
+	}
+}
+
+// ContainsAny checks that the `got` error's string contains at least one of
+// the substrings. As with [Any], an empty list of substrings can never be
+// satisfied, and as with [Contains], a nil error never matches.
+func ContainsAny(substrs ...string) Want {
+	desc := fmt.Sprintf("containing any of substrings %q", substrs)
+	return &described{
+		desc: desc,
+		match: func(got error) bool {
+			return messageMatches(got, func(msg string) bool {
+				for _, s := range substrs {
+					if strings.Contains(msg, s) {
+						return true
+					}
+				}
+				return false
+			})
+		},
+		diff: func(got error) string {
+			if len(substrs) == 0 {
+				return DiffMessage(got, "ContainsAny() of at least one substring")
+			}
+			if got == nil {
+				return DiffMessage(got, "%s", desc)
+			}
+
+			msg, ok := message(got)
+			if !ok {
+				return DiffMessage(got, "%s", desc)
+			}
+			for _, s := range substrs {
+				if strings.Contains(msg, s) {
+					return ""
+				}
+			}
+			return DiffMessage(got, "%s", desc)
+		},
+	}
+}
+
+// MessageIs checks that the `got` error's string is exactly `want`. On
+// mismatch, the diff reports the byte offset of the first divergence as well
+// as the surrounding text of both messages. A nil error never matches.
+func MessageIs(want string) Want {
+	desc := fmt.Sprintf("message %q", want)
+	return &described{
+		desc: desc,
+		match: func(got error) bool {
+			return messageMatches(got, func(msg string) bool { return msg == want })
+		},
+		diff: func(got error) string {
+			if got == nil {
+				return DiffMessage(got, "%s", desc)
+			}
+			msg, ok := message(got)
+			if !ok {
+				return DiffMessage(got, "%s", desc)
+			}
+			if msg == want {
+				return ""
+			}
+			i := divergence(msg, want)
+			return diffMessageAt(
+				got, i, "%s; first difference at byte %d: got %s; want %s",
+				desc, i, excerpt(msg, i), excerpt(want, i),
+			)
+		},
+	}
+}
+
+// messageMatches reports whether `got` is non-nil and `fn` returns true for
+// its [message]. A message that can't be obtained never matches.
+func messageMatches(got error, fn func(msg string) bool) bool {
+	if got == nil {
+		return false
+	}
+	msg, ok := message(got)
+	return ok && fn(msg)
+}
+
+// messagePredicate is the equivalent of [predicate] for a function of the
+// `got` error's message. A nil error, or one the message of which can't be
+// obtained, never matches.
+func messagePredicate(desc string, fn func(msg string) bool) *described {
+	return &described{
+		desc:  desc,
+		match: func(got error) bool { return messageMatches(got, fn) },
+		diff: func(got error) string {
+			if got != nil {
+				if msg, ok := message(got); ok && fn(msg) {
+					return ""
+				}
+			}
+			return DiffMessage(got, "%s", desc)
+		},
+	}
+}
+
+// divergence returns the index of the first byte at which `a` and `b` differ,
+// or the length of the shorter string if it is a prefix of the other.
+func divergence(a, b string) int {
+	n := min(len(a), len(b))
+	for i := range n {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// excerptContext is the number of bytes either side of an index included by
+// [excerpt].
+const excerptContext = 16
+
+// excerpt returns a quoted excerpt of `s` around index `i`, with ellipses
+// denoting omitted text. The bounds are moved outwards to UTF-8 rune
+// boundaries.
+func excerpt(s string, i int) string {
+	start := max(0, i-excerptContext)
+	for start > 0 && !utf8.RuneStart(s[start]) {
+		start--
+	}
+	end := min(len(s), i+excerptContext)
+	for end < len(s) && !utf8.RuneStart(s[end]) {
+		end++
+	}
+
+	var pre, post string
+	if start > 0 {
+		pre = "…"
+	}
+	if end < len(s) {
+		post = "…"
+	}
+	return fmt.Sprintf("%s%q%s", pre, s[start:end], post)
+}
+
+// A ContainsOption modifies the comparison performed by [ContainsWith].
+type ContainsOption func(*containsConfig)
+
+type containsConfig struct {
+	foldCase, collapseSpace bool
+}
+
+// FoldCase results in case-insensitive matching, under Unicode simple case
+// folding (e.g. "K", "k", and the Kelvin sign are equivalent but "ß" and "ss"
+// are not).
+func FoldCase() ContainsOption {
+	return func(c *containsConfig) { c.foldCase = true }
+}
+
+// CollapseSpace results in every run of Unicode whitespace, in both the
+// message and the substring, being treated as a single space.
+func CollapseSpace() ContainsOption {
+	return func(c *containsConfig) { c.collapseSpace = true }
+}
+
+// ContainsFold is equivalent to [ContainsWith] with [FoldCase].
+func ContainsFold(substr string) Want {
+	return ContainsWith(substr, FoldCase())
+}
+
+// ContainsWith is equivalent to [Contains] but normalizes both the `got`
+// error's string and the substring according to the options before comparing
+// them. Diffs report the original message, with the options listed after the
+// substring. Without any options, ContainsWith is identical to [Contains].
+func ContainsWith(substr string, opts ...ContainsOption) Want {
+	var cfg containsConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if !cfg.foldCase && !cfg.collapseSpace {
+		return Contains(substr)
+	}
+
+	var mods []string
+	if cfg.foldCase {
+		mods = append(mods, "case-insensitive")
+	}
+	if cfg.collapseSpace {
+		mods = append(mods, "whitespace-collapsed")
+	}
+	want := cfg.normalize(substr)
+
+	return messagePredicate(
+		fmt.Sprintf("containing substring %q (%s)", substr, strings.Join(mods, ", ")),
+		func(msg string) bool { return cfg.contains(msg, want) },
+	)
+}
+
+// contains is equivalent to `strings.Contains(c.normalize(msg), want)`, for an
+// already-normalized `want`, but doesn't allocate.
+func (c containsConfig) contains(msg, want string) bool {
+	for i := 0; ; {
+		if c.hasNormalizedPrefix(msg[i:], want) {
+			return true
+		}
+		if i == len(msg) {
+			return false
+		}
+		_, n := utf8.DecodeRuneInString(msg[i:])
+		i += n
+	}
+}
+
+// hasNormalizedPrefix reports whether the normalization of `s` begins with the
+// already-normalized `prefix`.
+func (c containsConfig) hasNormalizedPrefix(s, prefix string) bool {
+	for prefix != "" {
+		if s == "" {
+			return false
+		}
+		r, n := c.nextRune(s)
+		p, m := utf8.DecodeRuneInString(prefix)
+		if r != p {
+			return false
+		}
+		s, prefix = s[n:], prefix[m:]
+	}
+	return true
+}
+
+// nextRune returns the first rune of the normalization of `s`, and the number
+// of bytes of `s` from which it was derived.
+func (c containsConfig) nextRune(s string) (rune, int) {
+	r, n := utf8.DecodeRuneInString(s)
+	if c.collapseSpace && unicode.IsSpace(r) {
+		for n < len(s) {
+			next, m := utf8.DecodeRuneInString(s[n:])
+			if !unicode.IsSpace(next) {
+				break
+			}
+			n += m
+		}
+		return ' ', n
+	}
+	if c.foldCase {
+		r = foldRune(r)
+	}
+	return r, n
+}
+
+func (c containsConfig) normalize(s string) string {
+	if c.collapseSpace {
+		s = collapseSpace(s)
+	}
+	if c.foldCase {
+		s = strings.Map(foldRune, s)
+	}
+	return s
+}
+
+// collapseSpace replaces every run of whitespace with a single space.
+func collapseSpace(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	inSpace := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			if !inSpace {
+				b.WriteByte(' ')
+			}
+			inSpace = true
+			continue
+		}
+		inSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// foldRune returns the smallest rune in the Unicode simple case-folding orbit
+// of `r`, which is equal for all runes that are equivalent under folding.
+func foldRune(r rune) rune {
+	m := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		m = min(m, f)
+	}
+	return m
+}
@@ -0,0 +1,371 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+func ExampleMatchesRegexp() {
+	err := errors.New("dial tcp 127.0.0.1:43127: connection refused")
+
+	for _, want := range []testerr.Want{
+		testerr.MatchesRegexp(`^dial tcp 127\.0\.0\.1:\d+: `),
+		testerr.MatchesRegexp(`:\d+: timeout$`),
+		testerr.MatchesRegexp(`(unclosed`),
+		testerr.MatchesCompiledRegexp(regexp.MustCompile(`refused$`)),
+	} {
+		if diff := testerr.Diff(err, want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// got error dial tcp 127.0.0.1:43127: connection refused; want message matching regexp ":\\d+: timeout$"; message "dial tcp 127.0.0.1:43127: connection refused" does not match
+	// got error dial tcp 127.0.0.1:43127: connection refused; want message matching regexp "(unclosed" but pattern failed to compile: error parsing regexp: missing closing ): `(unclosed`
+	// <empty>
+}
+
+func ExampleHasPrefix() {
+	err := fmt.Errorf("loading config: %w", errors.New("unexpected EOF"))
+
+	for _, want := range []testerr.Want{
+		testerr.HasPrefix("loading config: "),
+		testerr.HasPrefix("config"),
+		testerr.HasPrefix(""),
+	} {
+		if diff := testerr.Diff(err, want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+	fmt.Println(testerr.Diff(nil, testerr.HasPrefix("")))
+
+	// Output:
+	// <empty>
+	// got error loading config: unexpected EOF; want message with prefix "config"
+	// <empty>
+	// got error <nil>; want message with prefix ""
+}
+
+func ExampleHasSuffix() {
+	err := fmt.Errorf("loading config: %w", errors.New("unexpected EOF"))
+
+	for _, want := range []testerr.Want{
+		testerr.HasSuffix(": unexpected EOF"),
+		testerr.HasSuffix("loading config"),
+		testerr.HasSuffix(""),
+	} {
+		if diff := testerr.Diff(err, want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+	fmt.Println(testerr.Diff(nil, testerr.HasSuffix("")))
+
+	// Output:
+	// <empty>
+	// got error loading config: unexpected EOF; want message with suffix "loading config"
+	// <empty>
+	// got error <nil>; want message with suffix ""
+}
+
+func ExampleContainsAll() {
+	err := errors.New(`PutObject "bucket/key": upstream status 503`)
+
+	for _, want := range []testerr.Want{
+		testerr.ContainsAll("PutObject", "bucket/key", "503"),
+		testerr.ContainsAll("PutObject", "GetObject", "503", "504"),
+		testerr.ContainsAll(""),
+		testerr.ContainsAll(),
+	} {
+		if diff := testerr.Diff(err, want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+	fmt.Println(testerr.Diff(nil, testerr.ContainsAll("")))
+
+	// Output:
+	// <empty>
+	// got error PutObject "bucket/key": upstream status 503; want containing all substrings ["PutObject" "GetObject" "503" "504"]; missing ["GetObject" "504"]
+	// <empty>
+	// got error PutObject "bucket/key": upstream status 503; want ContainsAll() of at least one substring
+	// got error <nil>; want containing all substrings [""]
+}
+
+func ExampleContainsAny() {
+	err := errors.New(`PutObject "bucket/key": upstream status 503`)
+
+	for _, want := range []testerr.Want{
+		testerr.ContainsAny("502", "503", "504"),
+		testerr.ContainsAny("502", "504"),
+		testerr.ContainsAny(""),
+		testerr.ContainsAny(),
+	} {
+		if diff := testerr.Diff(err, want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+	fmt.Println(testerr.Diff(nil, testerr.ContainsAny("")))
+
+	// Output:
+	// <empty>
+	// got error PutObject "bucket/key": upstream status 503; want containing any of substrings ["502" "504"]
+	// <empty>
+	// got error PutObject "bucket/key": upstream status 503; want ContainsAny() of at least one substring
+	// got error <nil>; want containing any of substrings [""]
+}
+
+func ExampleMessageIs() {
+	for _, err := range []error{
+		errors.New("user-facing message, exactly."),
+		errors.New("user-facing message, exactly!"),
+		errors.New("user-facing message, exactly. "),
+		errors.New("user-facing message"),
+		errors.New("a much longer user-facing message that differs by one word, exactly."),
+	} {
+		if diff := testerr.Diff(err, testerr.MessageIs("user-facing message, exactly.")); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// got error user-facing message, exactly!; want message "user-facing message, exactly."; first difference at byte 28: got …"message, exactly!"; want …"message, exactly."
+	// got error user-facing message, exactly. ; want message "user-facing message, exactly."; first difference at byte 29: got …"essage, exactly. "; want …"essage, exactly."
+	// got error user-facing message; want message "user-facing message, exactly."; first difference at byte 19: got …"r-facing message"; want …"r-facing message, exactly."
+	// got error a much longer user-facing message that differs by one word, exactly.; want message "user-facing message, exactly."; first difference at byte 0: got "a much longer us"…; want "user-facing mess"…
+}
+
+func TestMessageIs(t *testing.T) {
+	const want = "line one\nline two\nline three"
+
+	tests := []struct {
+		name     string
+		err      error
+		wantDiff string
+	}{
+		{
+			name: "exact multi-line",
+			err:  errors.New(want),
+		},
+		{
+			name:     "multi-line differing on second line",
+			err:      errors.New("line one\nline 2\nline three"),
+			wantDiff: `got error line one` + "\n" + `line 2` + "\n" + `line three; want message "line one\nline two\nline three"; first difference at byte 14: got "line one\nline 2\nline three"; want "line one\nline two\nline three"`,
+		},
+		{
+			name:     "trailing newline",
+			err:      errors.New(want + "\n"),
+			wantDiff: `got error line one` + "\n" + `line two` + "\n" + `line three` + "\n" + `; want message "line one\nline two\nline three"; first difference at byte 28: got …"e two\nline three\n"; want …"e two\nline three"`,
+		},
+		{
+			name:     "nil",
+			err:      nil,
+			wantDiff: `got error <nil>; want message "line one\nline two\nline three"`,
+		},
+		{
+			name:     "multi-byte rune at excerpt boundary",
+			err:      errors.New("∑∑∑∑∑∑∑∑"),
+			wantDiff: `got error ∑∑∑∑∑∑∑∑; want message "line one\nline two\nline three"; first difference at byte 0: got "∑∑∑∑∑∑"…; want "line one\nline tw"…`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testerr.Diff(tt.err, testerr.MessageIs(want)); got != tt.wantDiff {
+				t.Errorf("Diff(%q, MessageIs(%q))\ngot:  %s\nwant: %s", tt.err, want, got, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func ExampleContainsFold() {
+	for _, err := range []error{
+		errors.New("Access Denied"),
+		errors.New("access denied"),
+		errors.New("access\t  denied"),
+	} {
+		for _, want := range []testerr.Want{
+			testerr.ContainsFold("ACCESS DENIED"),
+			testerr.ContainsWith("access denied", testerr.FoldCase(), testerr.CollapseSpace()),
+		} {
+			if diff := testerr.Diff(err, want); diff != "" {
+				fmt.Println(diff)
+			} else {
+				fmt.Println("<empty>")
+			}
+		}
+	}
+
+	// Output:
+	// <empty>
+	// <empty>
+	// <empty>
+	// <empty>
+	// got error access	  denied; want containing substring "ACCESS DENIED" (case-insensitive)
+	// <empty>
+}
+
+func TestContainsWith(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		substr   string
+		opts     []testerr.ContainsOption
+		wantDiff bool
+	}{
+		{
+			name:   "no options is case-sensitive",
+			err:    errors.New("Access Denied"),
+			substr: "Access Denied",
+		},
+		{
+			name:     "no options is whitespace-sensitive",
+			err:      errors.New("Access  Denied"),
+			substr:   "Access Denied",
+			wantDiff: true,
+		},
+		{
+			name:     "no options with different case",
+			err:      errors.New("Access Denied"),
+			substr:   "access denied",
+			wantDiff: true,
+		},
+		{
+			name:   "Unicode folding",
+			err:    errors.New("ΣΊΣΥΦΟΣ"),
+			substr: "σίσυφος",
+			opts:   []testerr.ContainsOption{testerr.FoldCase()},
+		},
+		{
+			name:   "Kelvin sign",
+			err:    errors.New("273 \u212a"),
+			substr: "273 k",
+			opts:   []testerr.ContainsOption{testerr.FoldCase()},
+		},
+		{
+			name:   "dotted capital I",
+			err:    errors.New("İstanbul"),
+			substr: "İSTANBUL",
+			opts:   []testerr.ContainsOption{testerr.FoldCase()},
+		},
+		{
+			name:     "dotted capital I doesn't fold to ASCII",
+			err:      errors.New("İstanbul"),
+			substr:   "istanbul",
+			opts:     []testerr.ContainsOption{testerr.FoldCase()},
+			wantDiff: true,
+		},
+		{
+			name:     "sharp s out of scope",
+			err:      errors.New("straße"),
+			substr:   "STRASSE",
+			opts:     []testerr.ContainsOption{testerr.FoldCase()},
+			wantDiff: true,
+		},
+		{
+			name:   "collapsed whitespace in substring too",
+			err:    errors.New("a\n\n b"),
+			substr: "a \t b",
+			opts:   []testerr.ContainsOption{testerr.CollapseSpace()},
+		},
+		{
+			name:     "nil error",
+			substr:   "",
+			opts:     []testerr.ContainsOption{testerr.FoldCase(), testerr.CollapseSpace()},
+			wantDiff: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := testerr.Diff(tt.err, testerr.ContainsWith(tt.substr, tt.opts...))
+			if got := diff != ""; got != tt.wantDiff {
+				t.Errorf("Diff(%q, ContainsWith(%q, …)) got diff %q; want non-empty = %t", tt.err, tt.substr, diff, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func TestMatchesRegexp(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		want     testerr.Want
+		wantDiff bool
+	}{
+		{
+			name:     "nil error",
+			want:     testerr.MatchesRegexp(`.*`),
+			wantDiff: true,
+		},
+		{
+			name:     "nil error with compiled regexp",
+			want:     testerr.MatchesCompiledRegexp(regexp.MustCompile(`.*`)),
+			wantDiff: true,
+		},
+		{
+			name:     "nil compiled regexp",
+			err:      errors.New("anything"),
+			want:     testerr.MatchesCompiledRegexp(nil),
+			wantDiff: true,
+		},
+		{
+			name: "empty message matches empty pattern",
+			err:  errors.New(""),
+			want: testerr.MatchesRegexp(``),
+		},
+		{
+			name: "unanchored",
+			err:  errors.New("open /tmp/x123/config.json: no such file"),
+			want: testerr.MatchesRegexp(`/tmp/x\d+/`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := testerr.Diff(tt.err, tt.want)
+			if got := diff != ""; got != tt.wantDiff {
+				t.Errorf("Diff(%v, %s) got diff %q; want non-empty = %t", tt.err, testerr.Describe(tt.want), diff, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func TestMatchesRegexpConcurrentCompilation(t *testing.T) {
+	// The lazy compilation is only meaningfully tested with the race detector.
+	valid := testerr.MatchesRegexp(`^a+$`)
+	invalid := testerr.MatchesRegexp(`a++`)
+	err := errors.New("aaa")
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if diff := testerr.Diff(err, valid); diff != "" {
+				t.Errorf("Diff(%v, [shared valid regexp]) %s", err, diff)
+			}
+			if testerr.Diff(err, invalid) == "" {
+				t.Errorf("Diff(%v, [shared invalid regexp]) got empty diff", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,95 @@
+// Package neterr provides [testerr.Want] implementations for errors returned
+// by the [net] and [net/url] packages, including by [net/http] clients.
+package neterr
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// Timeout checks that a node in the `got` error's tree implements [net.Error],
+// as found by [errors.As], and that its `Timeout()` method returns true.
+func Timeout() testerr.Want {
+	return testerr.Implements(func(e net.Error) string {
+		if e.Timeout() {
+			return ""
+		}
+		return "Timeout() returned false"
+	})
+}
+
+// Temporary checks that a node in the `got` error's tree implements
+// [net.Error], as found by [errors.As], and that its deprecated `Temporary()`
+// method returns true. It exists for testing code that still relies on the
+// method; see the [net.Error] documentation for why new code SHOULD NOT.
+func Temporary() testerr.Want {
+	return testerr.Implements(func(e net.Error) string {
+		if e.Temporary() {
+			return ""
+		}
+		return "Temporary() returned false"
+	})
+}
+
+// URL checks that the `got` error unwraps, via [errors.As], to a [url.Error]
+// with the `op` (e.g. "Get"), a URL matching `urlWant`, and a wrapped error
+// matching `inner`. The URL is checked with [testerr.Diff] as an error of which
+// it is the string, so a nil `urlWant` never matches and `testerr.Contains("")`
+// SHOULD be used to accept any URL. The `inner` [testerr.Want] is checked against the
+// `Err` field, in the same manner as by [testerr.Diff], so a cancelled request
+// is matched by `testerr.Is(context.Canceled)`. The diff names every mismatched
+// field.
+func URL(op string, urlWant, inner testerr.Want) testerr.Want {
+	return testerr.As(func(got *url.Error) string {
+		var failed []string
+		if got.Op != op {
+			failed = append(failed, fmt.Sprintf("Op: got %q; want %q", got.Op, op))
+		}
+		if d := testerr.Diff(errors.New(got.URL), urlWant); d != "" {
+			failed = append(failed, "URL: "+d)
+		}
+		if d := testerr.Diff(got.Err, inner); d != "" {
+			failed = append(failed, "Err: "+d)
+		}
+		return mismatched(
+			fmt.Sprintf(
+				"*url.Error with Op %q, URL %s, and Err %s",
+				op, testerr.Describe(urlWant), testerr.Describe(inner),
+			),
+			failed,
+		)
+	})
+}
+
+// DNS checks that the `got` error unwraps, via [errors.As], to a
+// [net.DNSError] with a `Name` matching `hostWant` and with `IsNotFound` equal
+// to `notFound`. The host is checked in the same manner as the URL of [URL].
+func DNS(hostWant testerr.Want, notFound bool) testerr.Want {
+	return testerr.As(func(got *net.DNSError) string {
+		var failed []string
+		if d := testerr.Diff(errors.New(got.Name), hostWant); d != "" {
+			failed = append(failed, "Name: "+d)
+		}
+		if got.IsNotFound != notFound {
+			failed = append(failed, fmt.Sprintf("IsNotFound: got %t; want %t", got.IsNotFound, notFound))
+		}
+		return mismatched(
+			fmt.Sprintf("*net.DNSError with Name %s and IsNotFound %t", testerr.Describe(hostWant), notFound),
+			failed,
+		)
+	})
+}
+
+// mismatched returns the expectation reported by an [testerr.As] matching
+// function, or the empty string if no fields failed.
+func mismatched(desc string, failed []string) string {
+	if len(failed) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s; mismatched %s", desc, strings.Join(failed, "; "))
+}
@@ -0,0 +1,165 @@
+package neterr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+	"github.com/arr4n/shed/testerr/neterr"
+)
+
+type netError struct{ timeout, temporary bool }
+
+func (e netError) Error() string   { return "net error" }
+func (e netError) Timeout() bool   { return e.timeout }
+func (e netError) Temporary() bool { return e.temporary }
+
+func TestNetErr(t *testing.T) {
+	// Constructed as by [net/http.Client], without any network I/O.
+	notFound := &url.Error{
+		Op:  "Get",
+		URL: "https://missing.example/path",
+		Err: &net.OpError{
+			Op:  "dial",
+			Net: "tcp",
+			Err: &net.DNSError{
+				Err:        "no such host",
+				Name:       "missing.example",
+				IsNotFound: true,
+			},
+		},
+	}
+	cancelled := &url.Error{
+		Op:  "Post",
+		URL: "https://api.example/v1",
+		Err: fmt.Errorf("request: %w", context.Canceled),
+	}
+	timedOut := fmt.Errorf("client: %w", &url.Error{
+		Op:  "Get",
+		URL: "https://slow.example",
+		Err: &net.DNSError{
+			Err:       "i/o timeout",
+			Name:      "slow.example",
+			IsTimeout: true,
+		},
+	})
+
+	tests := []struct {
+		name     string
+		err      error
+		want     testerr.Want
+		wantDiff string // following "want"; empty if no diff is expected
+	}{
+		{
+			name: "Timeout() via url.Error",
+			err:  timedOut,
+			want: neterr.Timeout(),
+		},
+		{
+			name: "Timeout() of os.ErrDeadlineExceeded",
+			err:  fmt.Errorf("read: %w", os.ErrDeadlineExceeded),
+			want: neterr.Timeout(),
+		},
+		{
+			name:     "Timeout() false",
+			err:      notFound,
+			want:     neterr.Timeout(),
+			wantDiff: "error tree containing implementation of net.Error; found *url.Error but check failed: Timeout() returned false",
+		},
+		{
+			name:     "Timeout() without net.Error",
+			err:      errors.New("plain"),
+			want:     neterr.Timeout(),
+			wantDiff: "error tree containing implementation of net.Error; found types [*errors.errorString]",
+		},
+		{
+			name: "Temporary() returned false",
+			err:  fmt.Errorf("wrapped: %w", netError{temporary: true}),
+			want: neterr.Temporary(),
+		},
+		{
+			name:     "Temporary() false",
+			err:      netError{timeout: true},
+			want:     neterr.Temporary(),
+			wantDiff: "error tree containing implementation of net.Error; found neterr_test.netError but check failed: Temporary() returned false",
+		},
+		{
+			name: "URL() with inner DNS()",
+			err:  notFound,
+			want: neterr.URL(
+				"Get",
+				testerr.HasPrefix("https://missing.example/"),
+				neterr.DNS(testerr.MessageIs("missing.example"), true),
+			),
+		},
+		{
+			name: "URL() with cancelled context",
+			err:  fmt.Errorf("upload: %w", cancelled),
+			want: neterr.URL("Post", testerr.Contains("api.example"), testerr.Is(context.Canceled)),
+		},
+		{
+			name:     "URL() Op mismatch",
+			err:      cancelled,
+			want:     neterr.URL("Get", testerr.Contains(""), testerr.Is(context.Canceled)),
+			wantDiff: `*url.Error with Op "Get", URL containing substring "", and Err error that Is() context canceled; mismatched Op: got "Post"; want "Get"`,
+		},
+		{
+			name:     "URL() URL mismatch",
+			err:      cancelled,
+			want:     neterr.URL("Post", testerr.Contains("other.example"), testerr.Is(context.Canceled)),
+			wantDiff: `*url.Error with Op "Post", URL containing substring "other.example", and Err error that Is() context canceled; mismatched URL: got error https://api.example/v1; want containing substring "other.example"`,
+		},
+		{
+			name:     "URL() inner mismatch",
+			err:      cancelled,
+			want:     neterr.URL("Post", testerr.Contains(""), testerr.Is(context.DeadlineExceeded)),
+			wantDiff: `*url.Error with Op "Post", URL containing substring "", and Err error that Is() context deadline exceeded; mismatched Err: got error request: context canceled; want error that Is() context deadline exceeded`,
+		},
+		{
+			name:     "URL() of other type",
+			err:      context.Canceled,
+			want:     neterr.URL("Post", testerr.Contains(""), testerr.Is(context.Canceled)),
+			wantDiff: "error tree containing type *url.Error; found types [*errors.errorString]",
+		},
+		{
+			name: "DNS() deep in tree",
+			err:  timedOut,
+			want: neterr.DNS(testerr.MessageIs("slow.example"), false),
+		},
+		{
+			name:     "DNS() IsNotFound mismatch",
+			err:      notFound,
+			want:     neterr.DNS(testerr.Contains("missing"), false),
+			wantDiff: `*net.DNSError with Name containing substring "missing" and IsNotFound false; mismatched IsNotFound: got true; want false`,
+		},
+		{
+			name:     "DNS() Name mismatch",
+			err:      notFound,
+			want:     neterr.DNS(testerr.MessageIs("found.example"), true),
+			wantDiff: `*net.DNSError with Name message "found.example" and IsNotFound true; mismatched Name: got error missing.example; want message "found.example"; first difference at byte 0: got "missing.example"; want "found.example"`,
+		},
+		{
+			name:     "URL() reports nested DNS() layer",
+			err:      notFound,
+			want:     neterr.URL("Get", testerr.Contains(""), neterr.DNS(testerr.Contains(""), false)),
+			wantDiff: `*url.Error with Op "Get", URL containing substring "", and Err error tree containing type *net.DNSError; mismatched Err: got error dial tcp: lookup missing.example: no such host; want *net.DNSError with Name containing substring "" and IsNotFound false; mismatched IsNotFound: got true; want false`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want string
+			if tt.wantDiff != "" {
+				want = testerr.DiffMessage(tt.err, "%s", tt.wantDiff)
+			}
+			if diff := testerr.Diff(tt.err, tt.want); diff != want {
+				t.Errorf("Diff(%v, %s) got %q; want %q", tt.err, testerr.Describe(tt.want), diff, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package testerr
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// A Rule replaces all matches of `Pattern` in an error's message, as with
+// [regexp.Regexp.ReplaceAllString], so `Replacement` MAY refer to submatches.
+type Rule struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+func (r Rule) apply(s string) string {
+	return r.Pattern.ReplaceAllString(s, r.Replacement)
+}
+
+// ReplaceTempDirs returns a [Rule] that replaces paths to temporary
+// directories, as created by [os.MkdirTemp] or [testing.T.TempDir], with
+// "<tmpdir>". The directory's own name, and the numbered subdirectory created
+// by [testing.T.TempDir], are replaced but any deeper path elements are not.
+func ReplaceTempDirs() Rule {
+	tmp := regexp.QuoteMeta(strings.TrimRight(os.TempDir(), `/\`))
+	return Rule{
+		Pattern:     regexp.MustCompile(tmp + `[/\\][^/\\\s:'"]+(?:[/\\][0-9]{3}\b)?`),
+		Replacement: "<tmpdir>",
+	}
+}
+
+// ReplaceHex returns a [Rule] that replaces all hexadecimal strings of at least
+// `n` digits, optionally prefixed with 0x, with "<hex>". Note that this
+// includes sufficiently long decimal numbers.
+func ReplaceHex(n int) Rule {
+	return Rule{
+		Pattern:     regexp.MustCompile(fmt.Sprintf(`\b(?:0[xX])?[0-9a-fA-F]{%d,}\b`, n)),
+		Replacement: "<hex>",
+	}
+}
+
+// Normalize applies all of the `rules`, in order, to the `got` error's
+// message. The result is used as the message of a synthesized error that wraps
+// `got`, against which `want` is then checked in the same manner as by [Diff].
+//
+// Normalization therefore only affects [Want]s that inspect the message (e.g.
+// [Contains] or [MessageIs]) while those that inspect the error tree (e.g. [Is]
+// and [As]) still see `got` via unwrapping. [Equals] is the exception as the
+// synthesized error is never equal to `got`. A nil `got` error is passed to
+// `want` without normalization.
+func Normalize(want Want, rules ...Rule) Want {
+	return &described{
+		desc: fmt.Sprintf("%s after normalization", Describe(want)),
+		diff: func(got error) string {
+			if got == nil {
+				return Diff(got, want)
+			}
+
+			msg, ok := message(got)
+			if !ok {
+				return DiffMessage(got, "%s after normalization", Describe(want))
+			}
+			for _, r := range rules {
+				msg = r.apply(msg)
+			}
+			d := Diff(&normalizedError{msg, got}, want)
+			if d == "" {
+				return ""
+			}
+			return DiffMessage(
+				got, "%s after normalization:\n\t%s",
+				Describe(want), strings.ReplaceAll(d, "\n", "\n\t"),
+			)
+		},
+	}
+}
+
+// normalizedError is the synthesized error used by [Normalize].
+type normalizedError struct {
+	msg string
+	err error
+}
+
+func (e *normalizedError) Error() string { return e.msg }
+func (e *normalizedError) Unwrap() error { return e.err }
@@ -0,0 +1,122 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+func ExampleNormalize() {
+	err := errors.New("goroutine 1234 at 0xc000123abc: boom")
+
+	for _, want := range []testerr.Want{
+		testerr.Normalize(
+			testerr.MessageIs("goroutine <n> at <hex>: boom"),
+			testerr.ReplaceHex(8),
+			testerr.Rule{Pattern: regexp.MustCompile(`goroutine \d+`), Replacement: "goroutine <n>"},
+		),
+		testerr.Normalize(
+			testerr.Contains("at <hex>: bang"),
+			testerr.ReplaceHex(8),
+		),
+	} {
+		if diff := testerr.Diff(err, want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// got error goroutine 1234 at 0xc000123abc: boom; want containing substring "at <hex>: bang" after normalization:
+	// 	got error goroutine 1234 at <hex>: boom; want containing substring "at <hex>: bang"
+}
+
+func TestNormalize(t *testing.T) {
+	dir := t.TempDir()
+	_, errOpen := os.Open(filepath.Join(dir, "config.json"))
+	if errOpen == nil {
+		t.Fatal("os.Open(<non-existent file>) got nil error")
+	}
+	want := fmt.Sprintf("open %s: ", filepath.Join("<tmpdir>", "config.json"))
+
+	tests := []struct {
+		name     string
+		err      error
+		want     testerr.Want
+		wantDiff bool
+	}{
+		{
+			name: "temp dir replaced",
+			err:  errOpen,
+			want: testerr.Normalize(testerr.HasPrefix(want), testerr.ReplaceTempDirs()),
+		},
+		{
+			name:     "temp dir not replaced without rule",
+			err:      errOpen,
+			want:     testerr.Normalize(testerr.HasPrefix(want)),
+			wantDiff: true,
+		},
+		{
+			name: "Is() sees original error",
+			err:  errOpen,
+			want: testerr.Normalize(testerr.Is(fs.ErrNotExist), testerr.ReplaceTempDirs()),
+		},
+		{
+			name: "As() sees original error",
+			err:  errOpen,
+			want: testerr.Normalize(
+				testerr.As(func(*fs.PathError) string { return "" }),
+				testerr.ReplaceTempDirs(),
+			),
+		},
+		{
+			name:     "Equals() never matches",
+			err:      errOpen,
+			want:     testerr.Normalize(testerr.Equals(errOpen)),
+			wantDiff: true,
+		},
+		{
+			name: "rules applied in order",
+			err:  errors.New("a"),
+			want: testerr.Normalize(
+				testerr.MessageIs("c"),
+				testerr.Rule{Pattern: regexp.MustCompile(`a`), Replacement: "b"},
+				testerr.Rule{Pattern: regexp.MustCompile(`b`), Replacement: "c"},
+			),
+		},
+		{
+			name: "submatch replacement",
+			err:  errors.New("port 8080"),
+			want: testerr.Normalize(
+				testerr.MessageIs("<port> 8080"),
+				testerr.Rule{Pattern: regexp.MustCompile(`(\w+) (\d+)`), Replacement: "<$1> $2"},
+			),
+		},
+		{
+			name: "nil error with nil Want",
+			want: testerr.Normalize(nil, testerr.ReplaceHex(4)),
+		},
+		{
+			name:     "nil error with non-nil Want",
+			want:     testerr.Normalize(testerr.Contains(""), testerr.ReplaceHex(4)),
+			wantDiff: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := testerr.Diff(tt.err, tt.want)
+			if got := diff != ""; got != tt.wantDiff {
+				t.Errorf("Diff(%v, %s) got diff %q; want non-empty = %t", tt.err, testerr.Describe(tt.want), diff, tt.wantDiff)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !plan9
+
+package oserr
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// Errno checks that the `got` error unwraps, via [errors.As], to a
+// [syscall.Errno] equal to `want`. Errno values are platform specific so tests
+// that use Errno SHOULD either construct their errors or select the value with
+// build constraints; [NotExist] and [Permission] are portable alternatives.
+func Errno(want syscall.Errno) testerr.Want {
+	return testerr.As(func(got syscall.Errno) string {
+		if got == want {
+			return ""
+		}
+		return fmt.Sprintf(
+			"syscall.Errno %d (%v); got Errno %d (%v)",
+			uintptr(want), want, uintptr(got), got,
+		)
+	})
+}
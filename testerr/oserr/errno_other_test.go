@@ -0,0 +1,9 @@
+//go:build !plan9 && !windows
+
+package oserr_test
+
+import "syscall"
+
+// errnoNotExist is the Errno of opening a missing file in an existing
+// directory.
+const errnoNotExist = syscall.ENOENT
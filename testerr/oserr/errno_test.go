@@ -0,0 +1,37 @@
+//go:build !plan9
+
+package oserr_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+	"github.com/arr4n/shed/testerr/oserr"
+)
+
+func TestErrno(t *testing.T) {
+	missing := openMissing(t)
+	if diff := testerr.Diff(missing, oserr.Errno(errnoNotExist)); diff != "" {
+		t.Errorf("Diff(%v, Errno(%d)) %s", missing, errnoNotExist, diff)
+	}
+
+	denied := fmt.Errorf("save: %w", syscall.EACCES)
+	if diff := testerr.Diff(denied, oserr.Errno(syscall.EACCES)); diff != "" {
+		t.Errorf("Diff(%v, Errno(EACCES)) %s", denied, diff)
+	}
+
+	diff := testerr.Diff(denied, oserr.Errno(syscall.EPERM))
+	want := fmt.Sprintf("got Errno %d (%v)", uintptr(syscall.EACCES), syscall.EACCES)
+	if !strings.Contains(diff, want) {
+		t.Errorf("Diff(%v, Errno(EPERM)) got diff %q; want containing %q", denied, diff, want)
+	}
+
+	other := errors.New("no errno")
+	if diff := testerr.Diff(other, oserr.Errno(syscall.EACCES)); !strings.Contains(diff, "syscall.Errno") {
+		t.Errorf("Diff(%v, Errno(EACCES)) got diff %q; want mention of syscall.Errno", other, diff)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package oserr_test
+
+import "syscall"
+
+// errnoNotExist is the Errno of opening a missing file in an existing
+// directory.
+const errnoNotExist = syscall.ERROR_FILE_NOT_FOUND
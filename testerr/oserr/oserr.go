@@ -0,0 +1,68 @@
+// Package oserr provides [testerr.Want] implementations for errors returned by
+// the [os] and [io/fs] packages.
+//
+// Paths are compared in slash-separated form, as returned by
+// [filepath.ToSlash], so expectations are portable across platforms.
+package oserr
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// NotExist checks that the `got` error [errors.Is] [fs.ErrNotExist].
+func NotExist() testerr.Want {
+	return testerr.Predicate("error that Is() fs.ErrNotExist", func(got error) bool {
+		return errors.Is(got, fs.ErrNotExist)
+	})
+}
+
+// Permission checks that the `got` error [errors.Is] [fs.ErrPermission].
+func Permission() testerr.Want {
+	return testerr.Predicate("error that Is() fs.ErrPermission", func(got error) bool {
+		return errors.Is(got, fs.ErrPermission)
+	})
+}
+
+// Timeout checks that the `got` error [errors.Is] [os.ErrDeadlineExceeded], or
+// that a node in its tree has a `Timeout() bool` method that returns true.
+// Unlike [os.IsTimeout], wrapping errors are therefore supported.
+func Timeout() testerr.Want {
+	return testerr.Predicate("timeout error", func(got error) bool {
+		if errors.Is(got, os.ErrDeadlineExceeded) {
+			return true
+		}
+		var t interface{ Timeout() bool }
+		return errors.As(got, &t) && t.Timeout()
+	})
+}
+
+// PathError checks that the `got` error unwraps, via [errors.As], to an
+// [fs.PathError] with the `op` and a path matching `pathWant`. The path, in
+// slash-separated form, is checked with [testerr.Diff] as an error of which it
+// is the string, so a nil `pathWant` never matches and `testerr.Contains("")`
+// SHOULD be used to accept any path. The diff names every mismatched field.
+func PathError(op string, pathWant testerr.Want) testerr.Want {
+	return testerr.As(func(got *fs.PathError) string {
+		var failed []string
+		if got.Op != op {
+			failed = append(failed, fmt.Sprintf("Op: got %q; want %q", got.Op, op))
+		}
+		if d := testerr.Diff(errors.New(filepath.ToSlash(got.Path)), pathWant); d != "" {
+			failed = append(failed, "Path: "+d)
+		}
+		if len(failed) == 0 {
+			return ""
+		}
+		return fmt.Sprintf(
+			"*fs.PathError with Op %q and Path %s; mismatched %s",
+			op, testerr.Describe(pathWant), strings.Join(failed, "; "),
+		)
+	})
+}
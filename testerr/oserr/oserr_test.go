@@ -0,0 +1,149 @@
+package oserr_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+	"github.com/arr4n/shed/testerr/oserr"
+)
+
+// openMissing returns the error from opening a file that doesn't exist, in a
+// directory that does, so that the error is identical across platforms other
+// than its Errno.
+func openMissing(t *testing.T) error {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "sub")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	_, err := os.Open(filepath.Join(dir, "missing.txt"))
+	if err == nil {
+		t.Fatal("os.Open(<missing file>) got nil error")
+	}
+	return err
+}
+
+type timeoutError struct{ timeout bool }
+
+func (e timeoutError) Error() string { return fmt.Sprintf("timeout=%t", e.timeout) }
+func (e timeoutError) Timeout() bool { return e.timeout }
+
+func TestOSErr(t *testing.T) {
+	missing := openMissing(t)
+	// Constructed rather than provoked, as privileged users bypass file
+	// permissions.
+	denied := fmt.Errorf("save: %w", &fs.PathError{
+		Op:   "open",
+		Path: filepath.Join("var", "lib", "x"),
+		Err:  syscall.EACCES,
+	})
+
+	tests := []struct {
+		name     string
+		err      error
+		want     testerr.Want
+		wantDiff string // following "want"; empty if no diff is expected
+	}{
+		{
+			name: "NotExist() of missing file",
+			err:  missing,
+			want: oserr.NotExist(),
+		},
+		{
+			name:     "NotExist() of permission error",
+			err:      denied,
+			want:     oserr.NotExist(),
+			wantDiff: "error that Is() fs.ErrNotExist",
+		},
+		{
+			name: "Permission() of wrapped EACCES",
+			err:  denied,
+			want: oserr.Permission(),
+		},
+		{
+			name:     "Permission() of missing file",
+			err:      missing,
+			want:     oserr.Permission(),
+			wantDiff: "error that Is() fs.ErrPermission",
+		},
+		{
+			name: "Timeout() of wrapped os.ErrDeadlineExceeded",
+			err:  fmt.Errorf("read: %w", os.ErrDeadlineExceeded),
+			want: oserr.Timeout(),
+		},
+		{
+			name: "Timeout() of wrapped Timeout() method",
+			err:  fmt.Errorf("dial: %w", timeoutError{true}),
+			want: oserr.Timeout(),
+		},
+		{
+			name:     "Timeout() method returning false",
+			err:      timeoutError{false},
+			want:     oserr.Timeout(),
+			wantDiff: "timeout error",
+		},
+		{
+			name:     "Timeout() of nil",
+			want:     oserr.Timeout(),
+			wantDiff: "timeout error",
+		},
+		{
+			name: "PathError() with slash-separated path",
+			err:  missing,
+			want: oserr.PathError("open", testerr.HasSuffix("sub/missing.txt")),
+		},
+		{
+			name: "PathError() wrapped",
+			err:  denied,
+			want: oserr.PathError("open", testerr.MessageIs("var/lib/x")),
+		},
+		{
+			name:     "PathError() Op mismatch",
+			err:      missing,
+			want:     oserr.PathError("stat", testerr.Contains("")),
+			wantDiff: `*fs.PathError with Op "stat" and Path containing substring ""; mismatched Op: got "open"; want "stat"`,
+		},
+		{
+			name:     "PathError() Path mismatch",
+			err:      denied,
+			want:     oserr.PathError("open", testerr.Contains("etc")),
+			wantDiff: `*fs.PathError with Op "open" and Path containing substring "etc"; mismatched Path: got error var/lib/x; want containing substring "etc"`,
+		},
+		{
+			name:     "PathError() both fields mismatched",
+			err:      denied,
+			want:     oserr.PathError("remove", testerr.Contains("etc")),
+			wantDiff: `*fs.PathError with Op "remove" and Path containing substring "etc"; mismatched Op: got "open"; want "remove"; Path: got error var/lib/x; want containing substring "etc"`,
+		},
+		{
+			name:     "PathError() nil path Want",
+			err:      denied,
+			want:     oserr.PathError("open", nil),
+			wantDiff: `*fs.PathError with Op "open" and Path nil; mismatched Path: got error var/lib/x; want nil`,
+		},
+		{
+			name:     "PathError() of other type",
+			err:      errors.New("not a path"),
+			want:     oserr.PathError("open", testerr.Contains("")),
+			wantDiff: "error tree containing type *fs.PathError; found types [*errors.errorString]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want string
+			if tt.wantDiff != "" {
+				want = testerr.DiffMessage(tt.err, "%s", tt.wantDiff)
+			}
+			if diff := testerr.Diff(tt.err, tt.want); diff != want {
+				t.Errorf("Diff(%v, %s) got %q; want %q", tt.err, testerr.Describe(tt.want), diff, want)
+			}
+		})
+	}
+}
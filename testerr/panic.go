@@ -0,0 +1,104 @@
+package testerr
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// PanicValue is the error to which [Panics] converts a recovered value that
+// isn't itself an error. It can be inspected with [As] to access the original
+// value.
+type PanicValue struct {
+	Value any
+	// Stack holds the frames of the panicking goroutine, indented, as included
+	// in the diffs returned by [Panics]. It isn't part of the message.
+	Stack string
+}
+
+// Error returns the value formatted with %v.
+func (p PanicValue) Error() string {
+	return fmt.Sprintf("%v", p.Value)
+}
+
+// Panics calls `fn` and, if it panics, checks the recovered value against
+// `want`, in the same manner as by [Diff]. A recovered error is checked
+// directly while any other value is first converted to a [PanicValue]. A nil
+// `want` expects `fn` not to panic, mirroring the convention that it expects a
+// nil error; see [NoPanic]. The diff for an unexpected or mismatched panic
+// includes the stack of the panicking goroutine.
+//
+// If `fn` calls [runtime.Goexit], e.g. via `t.Fatal()`, Panics doesn't return
+// and the calling goroutine also exits, as it would without Panics.
+func Panics(fn func(), want Want) string {
+	r, stack, panicked := catchPanic(fn)
+	if !panicked {
+		if isNil(want) {
+			return ""
+		}
+		return fmt.Sprintf("got no panic; want panic with %s", Describe(want))
+	}
+
+	if isNil(want) {
+		return fmt.Sprintf("got panic with %T value %v; want no panic\n%s", r, r, stack)
+	}
+	err, ok := r.(error)
+	if !ok {
+		err = PanicValue{Value: r, Stack: stack}
+	}
+	if d := Diff(err, want); d != "" {
+		return fmt.Sprintf("after panic with %T value, %s\n%s", r, d, stack)
+	}
+	return ""
+}
+
+// NoPanic is equivalent to [Panics] with a nil [Want], checking that `fn`
+// doesn't panic.
+func NoPanic(fn func()) string {
+	return Panics(fn, nil)
+}
+
+// catchPanic calls `fn` and returns the recovered value, if it panicked, along
+// with the relevant frames of the stack trace.
+func catchPanic(fn func()) (recovered any, stack string, panicked bool) {
+	returned := false
+	defer func() {
+		if returned {
+			return
+		}
+		// As of Go 1.21, `panic(nil)` results in a non-nil
+		// [runtime.PanicNilError] so a nil value means [runtime.Goexit], which
+		// is allowed to continue unwinding.
+		if recovered = recover(); recovered != nil {
+			stack, panicked = panicFrames(debug.Stack()), true
+		}
+	}()
+	fn()
+	returned = true
+	return nil, "", false
+}
+
+// panicFrames returns the frames of a [debug.Stack] trace, captured while
+// recovering, between the call to `panic()` and [catchPanic], indented.
+func panicFrames(trace []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(trace)), "\n")
+	if len(lines) > 0 {
+		lines = lines[1:] // "goroutine N [running]:"
+	}
+
+	// Frames are pairs of lines: function then file:line.
+	var frames []string
+	for i := 0; i+1 < len(lines); i += 2 {
+		fn := lines[i]
+		switch {
+		case strings.HasPrefix(fn, "panic("):
+			frames = frames[:0] // discard recovery frames
+			continue
+		case strings.Contains(fn, "testerr.catchPanic("):
+			i = len(lines)
+			continue
+		}
+		frames = append(frames, "\t"+fn+"\n\t"+lines[i+1])
+	}
+	return strings.Join(frames, "\n")
+}
@@ -0,0 +1,149 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+func ExamplePanics() {
+	errInvariant := errors.New("invariant violated")
+	mustPositive := func(n int) {
+		if n <= 0 {
+			panic(fmt.Sprintf("non-positive %d", n))
+		}
+	}
+
+	tests := []struct {
+		name string
+		fn   func()
+		want testerr.Want
+	}{
+		{
+			name: "string value",
+			fn:   func() { mustPositive(-1) },
+			want: testerr.Contains("non-positive"),
+		},
+		{
+			name: "string value inspected with As()",
+			fn:   func() { mustPositive(-1) },
+			want: testerr.As(func(p testerr.PanicValue) string {
+				if s, ok := p.Value.(string); !ok || s != "non-positive -1" {
+					return `value "non-positive -1"`
+				}
+				return ""
+			}),
+		},
+		{
+			name: "error value",
+			fn:   func() { panic(fmt.Errorf("state: %w", errInvariant)) },
+			want: testerr.Is(errInvariant),
+		},
+		{
+			name: "no panic",
+			fn:   func() { mustPositive(1) },
+			want: testerr.Is(errInvariant),
+		},
+		{
+			name: "nil Want and no panic",
+			fn:   func() { mustPositive(1) },
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		fmt.Println("---", tt.name, "---")
+		if diff := testerr.Panics(tt.fn, tt.want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// --- string value ---
+	// <empty>
+	// --- string value inspected with As() ---
+	// <empty>
+	// --- error value ---
+	// <empty>
+	// --- no panic ---
+	// got no panic; want panic with error that Is() invariant violated
+	// --- nil Want and no panic ---
+	// <empty>
+}
+
+func panicWithEOF() { panic(io.EOF) }
+
+func TestPanicsDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+		want testerr.Want
+		// wantDiff are substrings of the expected diff.
+		wantDiff []string
+	}{
+		{
+			name:     "unexpected panic",
+			fn:       panicWithEOF,
+			want:     nil,
+			wantDiff: []string{"got panic with *errors.errorString value EOF; want no panic\n", "testerr_test.panicWithEOF()", "panic_test.go:"},
+		},
+		{
+			name:     "mismatched panic",
+			fn:       func() { panic(42) },
+			want:     testerr.Is(io.EOF),
+			wantDiff: []string{"after panic with int value, got error 42; want error that Is() EOF\n", "testerr_test.TestPanicsDiff."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := testerr.Panics(tt.fn, tt.want)
+			for _, want := range tt.wantDiff {
+				if !strings.Contains(diff, want) {
+					t.Errorf("Panics(…) got %q; want containing %q", diff, want)
+				}
+			}
+			for _, unwanted := range []string{"runtime/debug.Stack", "testerr.catchPanic", "testerr.Panics", "testing.tRunner"} {
+				if strings.Contains(diff, unwanted) {
+					t.Errorf("Panics(…) got %q; want stack without %q", diff, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestNoPanic(t *testing.T) {
+	if diff := testerr.NoPanic(func() {}); diff != "" {
+		t.Errorf("NoPanic(<no panic>) %s", diff)
+	}
+	if diff := testerr.NoPanic(func() { panic("oops") }); !strings.HasPrefix(diff, "got panic with string value oops; want no panic") {
+		t.Errorf("NoPanic(<panics>) got %q", diff)
+	}
+	if diff := testerr.NoPanic(func() { panic(nil) }); !strings.Contains(diff, "*runtime.PanicNilError") {
+		t.Errorf("NoPanic(<panic(nil)>) got %q; want reporting %T", diff, new(runtime.PanicNilError))
+	}
+}
+
+func TestPanicsGoexit(t *testing.T) {
+	returned := make(chan bool, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		testerr.NoPanic(runtime.Goexit)
+		returned <- true
+	}()
+	<-done
+
+	select {
+	case <-returned:
+		t.Error("NoPanic(runtime.Goexit) returned; want calling goroutine to exit")
+	default:
+	}
+}
@@ -0,0 +1,290 @@
+package testerr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A Registry provides [Parse] with the values that a spec refers to by name,
+// as Go can't resolve symbols from strings.
+type Registry struct {
+	// Errors maps names, conventionally qualified as in Go code (e.g.
+	// "io.EOF"), to the sentinel errors used by the `is:` kind.
+	Errors map[string]error
+	// Matchers maps additional kinds to constructors of [Want]s from their
+	// arguments. A constructor's error is reported as a [ParseError]. Kinds
+	// MUST NOT have the same names as those built into [Parse], which take
+	// precedence.
+	Matchers map[string]func(arg string) (Want, error)
+}
+
+// Parse parses `spec` into a [Want], allowing expectations to be expressed in
+// text, e.g. in script-driven tests. The resulting [Want]s are those of the
+// equivalent Go code so produce identical diffs.
+//
+// A spec is either the keyword `nil`, equivalent to [Nil], or a matcher of the
+// form `kind:arg`, any of which MAY be combined with the operators `!` ([Not]),
+// `&&` ([All]), and `||` ([Any]), in decreasing order of precedence, and
+// grouped with parentheses. Chains of the same binary operator are flattened
+// into a single [All] or [Any]. The built-in kinds are:
+//
+//	contains:S  [Contains]
+//	prefix:S    [HasPrefix]
+//	suffix:S    [HasSuffix]
+//	message:S   [MessageIs]
+//	regexp:S    [MatchesRegexp], compiled by Parse
+//	is:NAME     [Is] of Registry.Errors[NAME]
+//
+// and any others are looked up in [Registry.Matchers]. An argument is either a
+// Go string literal, double-quoted or raw, or bare text extending to the next
+// `&&`, `||`, or unbalanced `)`, with surrounding whitespace trimmed; e.g.
+// `regexp:^dial tcp && !contains:"(timeout)"`.
+//
+// The returned error is always a [*ParseError].
+func Parse(spec string, reg Registry) (Want, error) {
+	p := &parser{spec: spec, reg: reg}
+	w, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.spec) {
+		return nil, p.errorf(p.pos, "unexpected token")
+	}
+	return w, nil
+}
+
+// A ParseError describes a spec rejected by [Parse].
+type ParseError struct {
+	Spec   string
+	Offset int    // Byte offset of Token in Spec
+	Token  string // Empty at the end of Spec
+	Reason string
+}
+
+// Error returns a message including the position and token.
+func (e *ParseError) Error() string {
+	tok := "end of spec"
+	if e.Token != "" {
+		tok = strconv.Quote(e.Token)
+	}
+	return fmt.Sprintf("testerr.Parse(%q): %s at byte %d (%s)", e.Spec, e.Reason, e.Offset, tok)
+}
+
+type parser struct {
+	spec string
+	pos  int
+	reg  Registry
+}
+
+// errorf returns a [ParseError] for the token at byte offset `at`.
+func (p *parser) errorf(at int, format string, a ...any) *ParseError {
+	return p.errorTok(at, p.tokenAt(at), format, a...)
+}
+
+func (p *parser) errorTok(at int, tok, format string, a ...any) *ParseError {
+	return &ParseError{
+		Spec:   p.spec,
+		Offset: at,
+		Token:  tok,
+		Reason: fmt.Sprintf(format, a...),
+	}
+}
+
+var operators = []string{"&&", "||", "!", "(", ")"}
+
+// tokenAt returns the operator at byte offset `at`, if any, otherwise the
+// text up to the next whitespace or operator.
+func (p *parser) tokenAt(at int) string {
+	rest := p.spec[at:]
+	for _, op := range operators {
+		if strings.HasPrefix(rest, op) {
+			return op
+		}
+	}
+	end := strings.IndexFunc(rest, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || strings.ContainsRune("&|!()", r)
+	})
+	if end == -1 {
+		return rest
+	}
+	return rest[:end]
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.spec) && strings.IndexByte(" \t\n\r", p.spec[p.pos]) != -1 {
+		p.pos++
+	}
+}
+
+// consume skips whitespace and reports whether `op` follows, advancing past it
+// if so.
+func (p *parser) consume(op string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.spec[p.pos:], op) {
+		p.pos += len(op)
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (Want, error) {
+	return p.parseBinary("||", Any, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (Want, error) {
+	return p.parseBinary("&&", All, p.parseUnary)
+}
+
+func (p *parser) parseBinary(op string, combine func(...Want) Want, operand func() (Want, error)) (Want, error) {
+	var ws []Want
+	for {
+		w, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		ws = append(ws, w)
+		if !p.consume(op) {
+			break
+		}
+	}
+	if len(ws) == 1 {
+		return ws[0], nil
+	}
+	return combine(ws...), nil
+}
+
+func (p *parser) parseUnary() (Want, error) {
+	switch {
+	case p.consume("!"):
+		w, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(w), nil
+
+	case p.consume("("):
+		open := p.pos - 1
+		w, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, p.errorf(p.pos, `expected ")" to close "(" at byte %d`, open)
+		}
+		return w, nil
+
+	default:
+		return p.parseMatcher()
+	}
+}
+
+func (p *parser) parseMatcher() (Want, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.spec) && isKindByte(p.spec[p.pos]) {
+		p.pos++
+	}
+	kind := p.spec[start:p.pos]
+	if kind == "" {
+		return nil, p.errorf(start, "expected matcher")
+	}
+	if !strings.HasPrefix(p.spec[p.pos:], ":") {
+		if kind == "nil" {
+			return Nil(), nil
+		}
+		return nil, p.errorTok(start, kind, `expected ":" after kind`)
+	}
+	p.pos++
+
+	argStart := p.pos
+	arg, err := p.parseArg()
+	if err != nil {
+		return nil, err
+	}
+	raw := strings.TrimSpace(p.spec[argStart:p.pos])
+	argErr := func(format string, a ...any) *ParseError {
+		at := argStart + strings.Index(p.spec[argStart:], raw)
+		return p.errorTok(at, raw, format, a...)
+	}
+
+	switch kind {
+	case "contains":
+		return Contains(arg), nil
+	case "prefix":
+		return HasPrefix(arg), nil
+	case "suffix":
+		return HasSuffix(arg), nil
+	case "message":
+		return MessageIs(arg), nil
+	case "regexp":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, argErr("invalid regexp: %v", err)
+		}
+		return MatchesCompiledRegexp(re), nil
+	case "is":
+		target, ok := p.reg.Errors[arg]
+		if !ok {
+			return nil, argErr("unknown error name")
+		}
+		return Is(target), nil
+	}
+
+	ctor, ok := p.reg.Matchers[kind]
+	if !ok {
+		return nil, p.errorTok(start, kind, "unknown matcher kind")
+	}
+	w, err := ctor(arg)
+	if err != nil {
+		return nil, argErr("%s: %v", kind, err)
+	}
+	return w, nil
+}
+
+func isKindByte(c byte) bool {
+	return c == '_' || c == '-' || c == '.' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// parseArg parses a matcher's argument, as described by [Parse], returning it
+// unquoted.
+func (p *parser) parseArg() (string, error) {
+	p.skipSpace()
+	rest := p.spec[p.pos:]
+	if rest != "" && (rest[0] == '"' || rest[0] == '`') {
+		lit, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return "", p.errorTok(p.pos, rest, "unterminated or invalid string literal")
+		}
+		s, err := strconv.Unquote(lit)
+		if err != nil {
+			return "", p.errorTok(p.pos, lit, "invalid string literal")
+		}
+		p.pos += len(lit)
+		return s, nil
+	}
+
+	start := p.pos
+	var depth int
+scan:
+	for ; p.pos < len(p.spec); p.pos++ {
+		switch rest := p.spec[p.pos:]; {
+		case strings.HasPrefix(rest, "&&"), strings.HasPrefix(rest, "||"):
+			break scan
+		case rest[0] == '(':
+			depth++
+		case rest[0] == ')':
+			if depth == 0 {
+				break scan
+			}
+			depth--
+		}
+	}
+	arg := strings.TrimSpace(p.spec[start:p.pos])
+	if arg == "" {
+		return "", p.errorf(start, "expected argument; use \"\" for an empty string")
+	}
+	return arg, nil
+}
@@ -0,0 +1,246 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+var parseRegistry = testerr.Registry{
+	Errors: map[string]error{
+		"io.EOF":           io.EOF,
+		"io.ErrClosedPipe": io.ErrClosedPipe,
+		"fs.ErrNotExist":   fs.ErrNotExist,
+	},
+	Matchers: map[string]func(string) (testerr.Want, error){
+		"joined-len": func(arg string) (testerr.Want, error) {
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				return nil, err
+			}
+			return testerr.JoinedLen(n), nil
+		},
+	},
+}
+
+func ExampleParse() {
+	err := fmt.Errorf("read config: %w", io.EOF)
+
+	for _, spec := range []string{
+		`is:io.EOF && contains:"config"`,
+		`nil`,
+		`regexp:^read \w+: && !is:io.EOF`,
+		`is:io.EOF && contains:"json"`,
+	} {
+		want, perr := testerr.Parse(spec, parseRegistry)
+		if perr != nil {
+			fmt.Println(perr)
+			continue
+		}
+		printDiff(err, want)
+	}
+
+	_, perr := testerr.Parse(`is:io.EOF && contains:"json" || is:io.ErrUnexpectedEOF`, parseRegistry)
+	fmt.Println(perr)
+
+	// Output:
+	// <empty>
+	// got error read config: EOF; want nil
+	// got error read config: EOF; want all of 2 expectations; 1 failed:
+	// 	[1] got error read config: EOF; want NOT (error that Is() EOF)
+	// got error read config: EOF; want all of 2 expectations; 1 failed:
+	// 	[1] got error read config: EOF; want containing substring "json"
+	// testerr.Parse("is:io.EOF && contains:\"json\" || is:io.ErrUnexpectedEOF"): unknown error name at byte 35 ("io.ErrUnexpectedEOF")
+}
+
+// ExampleParse_testscript demonstrates a script command that checks the error
+// of an earlier command against a spec. With testscript, the body of `errCmd`
+// would be registered in Params.Cmds, using ts.Fatalf() to report failures.
+func ExampleParse_testscript() {
+	dir, err := os.MkdirTemp("", "parse-example")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "present.txt"), nil, 0o600); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	script := `
+open missing.txt
+err is:fs.ErrNotExist && contains:"missing.txt"
+open present.txt
+err nil
+err is:fs.ErrNotExist
+`
+
+	var last error
+	errCmd := func(args []string) {
+		want, err := testerr.Parse(strings.Join(args, " "), parseRegistry)
+		if err != nil {
+			fmt.Println("FAIL:", err)
+			return
+		}
+		if diff := testerr.Diff(last, want); diff != "" {
+			fmt.Println("FAIL: last error mismatch:", diff)
+			return
+		}
+		fmt.Println("PASS:", args)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(script), "\n") {
+		switch cmd, args := strings.Fields(line)[0], strings.Fields(line)[1:]; cmd {
+		case "open":
+			var f *os.File
+			f, last = os.Open(filepath.Join(dir, args[0]))
+			if last == nil {
+				f.Close()
+			}
+		case "err":
+			errCmd(args)
+		}
+	}
+
+	// Output:
+	// PASS: [is:fs.ErrNotExist && contains:"missing.txt"]
+	// PASS: [nil]
+	// FAIL: last error mismatch: got error <nil>; want error that Is() file does not exist
+}
+
+func TestParse(t *testing.T) {
+	errs := []error{
+		nil,
+		io.EOF,
+		fmt.Errorf("read: %w", io.EOF),
+		errors.New(`disk "full"`),
+		errors.New("dial tcp 127.0.0.1:80: connection refused"),
+		errors.New("dial tcp (timeout)"),
+		errors.Join(io.EOF, io.ErrClosedPipe),
+	}
+
+	tests := []struct {
+		spec string
+		want testerr.Want
+	}{
+		{"nil", testerr.Nil()},
+		{"  nil  ", testerr.Nil()},
+		{`contains:"disk full"`, testerr.Contains("disk full")},
+		{`contains: "read" `, testerr.Contains("read")},
+		{`contains:read`, testerr.Contains("read")},
+		{`contains:""`, testerr.Contains("")},
+		{`contains:"disk \"full\""`, testerr.Contains(`disk "full"`)},
+		{"contains:`disk \"full\"`", testerr.Contains(`disk "full"`)},
+		{`contains:"☃ && || )"`, testerr.Contains("☃ && || )")},
+		{`prefix:dial tcp`, testerr.HasPrefix("dial tcp")},
+		{`suffix:refused`, testerr.HasSuffix("refused")},
+		{`message:EOF`, testerr.MessageIs("EOF")},
+		{`regexp:^dial tcp`, testerr.MatchesRegexp("^dial tcp")},
+		{`regexp:\((timeout|refused)\)$`, testerr.MatchesRegexp(`\((timeout|refused)\)$`)},
+		{`is:io.EOF`, testerr.Is(io.EOF)},
+		{`!is:io.EOF`, testerr.Not(testerr.Is(io.EOF))},
+		{`!!is:io.EOF`, testerr.Not(testerr.Not(testerr.Is(io.EOF)))},
+		{`!nil`, testerr.Not(testerr.Nil())},
+		{`joined-len:2`, testerr.JoinedLen(2)},
+		{
+			`is:io.EOF && contains:"read"`,
+			testerr.All(testerr.Is(io.EOF), testerr.Contains("read")),
+		},
+		{
+			`is:io.EOF&&contains:read&&!is:io.ErrClosedPipe`,
+			testerr.All(testerr.Is(io.EOF), testerr.Contains("read"), testerr.Not(testerr.Is(io.ErrClosedPipe))),
+		},
+		{
+			`nil || is:io.EOF || prefix:dial`,
+			testerr.Any(testerr.Nil(), testerr.Is(io.EOF), testerr.HasPrefix("dial")),
+		},
+		{
+			`is:io.EOF && contains:read || nil`,
+			testerr.Any(testerr.All(testerr.Is(io.EOF), testerr.Contains("read")), testerr.Nil()),
+		},
+		{
+			`nil || is:io.EOF && contains:read`,
+			testerr.Any(testerr.Nil(), testerr.All(testerr.Is(io.EOF), testerr.Contains("read"))),
+		},
+		{
+			`(nil || is:io.EOF) && !(contains:read)`,
+			testerr.All(testerr.Any(testerr.Nil(), testerr.Is(io.EOF)), testerr.Not(testerr.Contains("read"))),
+		},
+		{
+			`((regexp:\((timeout)\)))`,
+			testerr.MatchesRegexp(`\((timeout)\)`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := testerr.Parse(tt.spec, parseRegistry)
+			if err != nil {
+				t.Fatalf("Parse(%q) error %v", tt.spec, err)
+			}
+			if g, w := testerr.Describe(got), testerr.Describe(tt.want); g != w {
+				t.Errorf("Describe(Parse(%q)) got %q; want %q", tt.spec, g, w)
+			}
+			for _, e := range errs {
+				if g, w := testerr.Diff(e, got), testerr.Diff(e, tt.want); g != w {
+					t.Errorf("Diff(%v, Parse(%q)) got %q; want %q", e, tt.spec, g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		spec       string
+		wantOffset int
+		wantToken  string
+		wantReason string // substring
+	}{
+		{"", 0, "", "expected matcher"},
+		{"   ", 3, "", "expected matcher"},
+		{"is:io.EOF &&", 12, "", "expected matcher"},
+		{"&& is:io.EOF", 0, "&&", "expected matcher"},
+		{"is:io.EOF && || nil", 13, "||", "expected matcher"},
+		{"contains", 0, "contains", `expected ":" after kind`},
+		{"nil nil", 4, "nil", "unexpected token"},
+		{"nil )", 4, ")", "unexpected token"},
+		{`contains:"x" prefix:y`, 13, "prefix:y", "unexpected token"},
+		{"contains:", 9, "", "expected argument"},
+		{"contains: && nil", 10, "&&", "expected argument"},
+		{`contains:"unterminated`, 9, `"unterminated`, "unterminated or invalid string literal"},
+		{`contains:"bad \q escape"`, 9, `"bad \q escape"`, "invalid string literal"},
+		{"is:io.ErrUnexpectedEOF", 3, "io.ErrUnexpectedEOF", "unknown error name"},
+		{"nil || is: os.ErrNotExist ", 11, "os.ErrNotExist", "unknown error name"},
+		{"equals:io.EOF", 0, "equals", "unknown matcher kind"},
+		{"regexp:(", 7, "(", "invalid regexp: error parsing regexp"},
+		{"joined-len:two", 11, "two", `joined-len: strconv.Atoi: parsing "two": invalid syntax`},
+		{"(nil || is:io.EOF", 17, "", `expected ")" to close "(" at byte 0`},
+		{"!(nil", 5, "", `expected ")" to close "(" at byte 1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			w, err := testerr.Parse(tt.spec, parseRegistry)
+			if w != nil {
+				t.Errorf("Parse(%q) got non-nil Want %s", tt.spec, testerr.Describe(w))
+			}
+			pe, ok := testerr.TryGet[*testerr.ParseError](err)
+			if ok != "" {
+				t.Fatalf("Parse(%q) error %s", tt.spec, ok)
+			}
+			if pe.Spec != tt.spec || pe.Offset != tt.wantOffset || pe.Token != tt.wantToken || !strings.Contains(pe.Reason, tt.wantReason) {
+				t.Errorf("Parse(%q) got %+v; want Offset %d, Token %q, and Reason containing %q", tt.spec, *pe, tt.wantOffset, tt.wantToken, tt.wantReason)
+			}
+		})
+	}
+}
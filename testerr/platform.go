@@ -0,0 +1,91 @@
+package testerr
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// goos is the value of [runtime.GOOS] used by [ByGOOS], which tests override.
+var goos = runtime.GOOS
+
+// ByGOOS selects, at the time of matching, the [Want] in `wants` keyed by
+// [runtime.GOOS], or `fallback` if there is no such key. This allows a single
+// test to hold expectations that differ between platforms, such as the
+// messages of [os] errors. A nil [Want] in `wants` is treated in the same manner
+// as by [Diff], so requires a nil error, but a nil `fallback` denotes the lack
+// of one, in which case an unlisted GOOS always results in a diff; use [Nil]
+// for a fallback that requires a nil error.
+//
+// On mismatch, the expectation is prefixed by the GOOS, e.g. "want
+// (GOOS=windows): …", which also applies to the [Describe] value.
+func ByGOOS(wants map[string]Want, fallback Want) Want {
+	return &selectedWant{
+		pick: func() (Want, bool) {
+			if w, ok := wants[goos]; ok {
+				return w, true
+			}
+			return fallback, !isNil(fallback)
+		},
+		label: func() string {
+			if _, ok := wants[goos]; ok {
+				return fmt.Sprintf("(GOOS=%s)", goos)
+			}
+			if !isNil(fallback) {
+				return fmt.Sprintf("(GOOS=%s) fallback", goos)
+			}
+			return fmt.Sprintf("ByGOOS() with Want for GOOS=%s or a fallback; got neither", goos)
+		},
+	}
+}
+
+// If selects `then` if `cond` is true, otherwise `els`. Either [Want] MAY be
+// nil, which is treated in the same manner as by [Diff]. As with [ByGOOS], the
+// selection is reported in diffs, as "(If() true)" or "(If() false)".
+func If(cond bool, then, els Want) Want {
+	w := els
+	if cond {
+		w = then
+	}
+	label := fmt.Sprintf("(If() %t)", cond)
+	return &selectedWant{
+		pick:  func() (Want, bool) { return w, true },
+		label: func() string { return label },
+	}
+}
+
+// selectedWant is a [Describer] that delegates to a [Want] selected by `pick`.
+// The `label` prefixes the delegate's expectation or, if there is no delegate,
+// describes the problem. It is only called once the label is needed, so
+// matching doesn't allocate.
+type selectedWant struct {
+	pick  func() (w Want, ok bool)
+	label func() string
+}
+
+func (s *selectedWant) ErrDiff(got error) string {
+	w, ok := s.pick()
+	if !ok {
+		return DiffMessage(got, "%s", s.label())
+	}
+	d := Diff(got, w)
+	if d == "" {
+		return ""
+	}
+	label := s.label()
+	// Including the separator, as for [DiffOpts], reliably locates the start of
+	// the expectation, other than in renderings of truncated messages.
+	sep := renderGot(got, 0) + "; want "
+	if rest, ok := strings.CutPrefix(d, sep); ok {
+		return sep + label + ": " + rest
+	}
+	return DiffMessage(got, "%s: %s:\n\t%s", label, Describe(w), strings.ReplaceAll(d, "\n", "\n\t"))
+}
+
+func (s *selectedWant) Describe() string {
+	w, ok := s.pick()
+	if !ok {
+		return s.label()
+	}
+	return s.label() + ": " + Describe(w)
+}
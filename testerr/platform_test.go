@@ -0,0 +1,189 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+func ExampleByGOOS() {
+	_, err := os.Open(filepath.Join(os.TempDir(), "testerr-does-not-exist"))
+
+	want := testerr.ByGOOS(map[string]testerr.Want{
+		"windows": testerr.Contains("The system cannot find the file specified"),
+	}, testerr.Contains("no such file or directory"))
+
+	fmt.Println(testerr.Diff(err, want) == "")
+
+	// Output:
+	// true
+}
+
+func TestByGOOS(t *testing.T) {
+	wants := map[string]testerr.Want{
+		"linux":   testerr.Contains("no such file or directory"),
+		"windows": testerr.Contains("The system cannot find the file specified"),
+		"plan9":   nil,
+	}
+	errLinux := errors.New("open x: no such file or directory")
+
+	tests := []struct {
+		name     string
+		goos     string
+		fallback testerr.Want
+		err      error
+		wantDiff string
+		wantDesc string
+	}{
+		{
+			name:     "selected",
+			goos:     "linux",
+			err:      errLinux,
+			wantDesc: `(GOOS=linux): containing substring "no such file or directory"`,
+		},
+		{
+			name:     "selected mismatch",
+			goos:     "windows",
+			err:      errLinux,
+			wantDiff: `got error open x: no such file or directory; want (GOOS=windows): containing substring "The system cannot find the file specified"`,
+			wantDesc: `(GOOS=windows): containing substring "The system cannot find the file specified"`,
+		},
+		{
+			name:     "nil Want requires nil error",
+			goos:     "plan9",
+			err:      errLinux,
+			wantDiff: "got error open x: no such file or directory; want (GOOS=plan9): nil",
+			wantDesc: "(GOOS=plan9): nil",
+		},
+		{
+			name: "nil Want with nil error",
+			goos: "plan9",
+		},
+		{
+			name:     "fallback",
+			goos:     "darwin",
+			fallback: testerr.Is(io.EOF),
+			err:      errLinux,
+			wantDiff: "got error open x: no such file or directory; want (GOOS=darwin) fallback: error that Is() EOF",
+			wantDesc: "(GOOS=darwin) fallback: error that Is() EOF",
+		},
+		{
+			name:     "Nil() fallback",
+			goos:     "darwin",
+			fallback: testerr.Nil(),
+			wantDesc: "(GOOS=darwin) fallback: nil",
+		},
+		{
+			name:     "neither",
+			goos:     "darwin",
+			wantDiff: "got error <nil>; want ByGOOS() with Want for GOOS=darwin or a fallback; got neither",
+			wantDesc: "ByGOOS() with Want for GOOS=darwin or a fallback; got neither",
+		},
+		{
+			name:     "typed-nil fallback is neither",
+			goos:     "darwin",
+			fallback: testerr.Func(nil),
+			wantDiff: "got error <nil>; want ByGOOS() with Want for GOOS=darwin or a fallback; got neither",
+			wantDesc: "ByGOOS() with Want for GOOS=darwin or a fallback; got neither",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testerr.SetGOOS(t, tt.goos)
+			want := testerr.ByGOOS(wants, tt.fallback)
+
+			if got := testerr.Diff(tt.err, want); got != tt.wantDiff {
+				t.Errorf("Diff(%v, ByGOOS(…)) with GOOS=%s got %q; want %q", tt.err, tt.goos, got, tt.wantDiff)
+			}
+			if tt.wantDesc == "" {
+				return
+			}
+			if got := testerr.Describe(want); got != tt.wantDesc {
+				t.Errorf("Describe(ByGOOS(…)) with GOOS=%s got %q; want %q", tt.goos, got, tt.wantDesc)
+			}
+		})
+	}
+
+	t.Run("selected at match time", func(t *testing.T) {
+		want := testerr.ByGOOS(wants, nil)
+		testerr.SetGOOS(t, "windows")
+		if diff := testerr.Diff(errors.New("The system cannot find the file specified."), want); diff != "" {
+			t.Errorf("Diff(…) after SetGOOS(windows) %s", diff)
+		}
+	})
+
+	t.Run("host", func(t *testing.T) {
+		want := testerr.ByGOOS(map[string]testerr.Want{runtime.GOOS: nil}, nil)
+		if diff := testerr.Diff(nil, want); diff != "" {
+			t.Errorf("Diff(nil, ByGOOS(<host GOOS>: nil)) %s", diff)
+		}
+	})
+}
+
+func TestIf(t *testing.T) {
+	tests := []struct {
+		name       string
+		cond       bool
+		then, els  testerr.Want
+		err        error
+		wantDiff   string
+		wantPrefix bool
+	}{
+		{
+			name: "then",
+			cond: true,
+			then: testerr.Is(io.EOF),
+			err:  io.EOF,
+		},
+		{
+			name:     "else mismatch",
+			cond:     false,
+			then:     testerr.Is(io.EOF),
+			els:      testerr.Contains("x"),
+			err:      io.EOF,
+			wantDiff: `got error EOF; want (If() false): containing substring "x"`,
+		},
+		{
+			name:     "nil then",
+			cond:     true,
+			err:      io.EOF,
+			wantDiff: "got error EOF; want (If() true): nil",
+		},
+		{
+			name: "nil else",
+			cond: false,
+			then: testerr.Is(io.EOF),
+		},
+		{
+			name:       "long message",
+			cond:       true,
+			then:       testerr.Is(io.EOF),
+			err:        errors.New(strings.Repeat("x", 2000)),
+			wantDiff:   "got error of 2000 bytes:",
+			wantPrefix: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testerr.Diff(tt.err, testerr.If(tt.cond, tt.then, tt.els))
+			if tt.wantPrefix {
+				if !strings.HasPrefix(got, tt.wantDiff) || !strings.HasSuffix(got, "; want (If() true): error that Is() EOF") {
+					t.Errorf("Diff(<long>, If(…)) got %q; want prefix %q and the selection", got, tt.wantDiff)
+				}
+				return
+			}
+			if got != tt.wantDiff {
+				t.Errorf("Diff(%v, If(%t, …)) got %q; want %q", tt.err, tt.cond, got, tt.wantDiff)
+			}
+		})
+	}
+}
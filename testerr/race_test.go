@@ -0,0 +1,7 @@
+//go:build race
+
+package testerr_test
+
+// raceEnabled is true when the race detector is enabled, which results in
+// allocations that wouldn't otherwise occur.
+const raceEnabled = true
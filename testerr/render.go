@@ -0,0 +1,205 @@
+package testerr
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// A DiffOption modifies how [DiffOpts] renders the `got` error.
+type DiffOption func(*diffConfig)
+
+type diffConfig struct {
+	quote, showType, verbose bool
+}
+
+// Quote renders the `got` error's message with %q, making trailing whitespace
+// and non-printable characters visible.
+func Quote() DiffOption {
+	return func(c *diffConfig) { c.quote = true }
+}
+
+// ShowType appends the concrete type of the `got` error.
+func ShowType() DiffOption {
+	return func(c *diffConfig) { c.showType = true }
+}
+
+// Verbose renders the `got` error with %+v instead of %v, for types that
+// implement [fmt.Formatter] to provide more detail, such as stack traces.
+func Verbose() DiffOption {
+	return func(c *diffConfig) { c.verbose = true }
+}
+
+// DiffOpts is equivalent to [Diff] except that every canonical rendering of the
+// `got` error in the diff, including those nested in the diffs of [Want]s such
+// as [All], is modified according to the options. Without any options,
+// DiffOpts is identical to [Diff].
+//
+// Nil and typed-nil errors, as well as long messages that are truncated, are
+// rendered as described for [DiffMessage], only affected by [ShowType].
+func DiffOpts(got error, want Want, opts ...DiffOption) string {
+	diff := Diff(got, want)
+	if diff == "" || len(opts) == 0 || got == nil {
+		return diff
+	}
+
+	var cfg diffConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if _, ok := typedNil(got); ok {
+		return diff
+	}
+	msg, ok := message(got)
+	if !ok {
+		return diff
+	}
+	if len(msg) > longMessage {
+		if !cfg.showType {
+			return diff
+		}
+		header := fmt.Sprintf("got error of %d bytes:", len(msg))
+		return strings.ReplaceAll(diff, header, fmt.Sprintf("got error of type %T, %d bytes:", got, len(msg)))
+	}
+	// Including the separator avoids modifying the renderings of other errors
+	// with messages that have the same prefix.
+	return strings.ReplaceAll(diff, renderGot(got, 0)+"; want ", cfg.render(got)+"; want ")
+}
+
+// render is the equivalent of [renderGot] for messages that aren't truncated.
+func (c *diffConfig) render(got error) string {
+	verb := "%v"
+	if c.verbose {
+		verb = "%+v"
+	}
+	msg := fmt.Sprintf(verb, got)
+	if c.quote {
+		msg = fmt.Sprintf("%q", msg)
+	}
+	if c.showType {
+		return fmt.Sprintf("got error %s of type %T", msg, got)
+	}
+	return "got error " + msg
+}
+
+// longMessage is the length, in bytes, above which messages of got errors are
+// truncated in diffs. Shorter messages are rendered in full, with %v.
+const longMessage = 1024
+
+// longMessageContext is the number of bytes, either side of the region of
+// interest, that are retained when truncating a long message.
+const longMessageContext = 128
+
+// diffMessageAt is equivalent to [DiffMessage] except that, if the `got`
+// error's message is long, the excerpt is centred on byte offset `at`.
+func diffMessageAt(got error, at int, wantFormat string, a ...any) string {
+	return fmt.Sprintf("%s; want %s", renderGot(got, at), fmt.Sprintf(wantFormat, a...))
+}
+
+// renderGot renders the `got` error for the start of a diff message; see
+// [diffMessageAt] re `at`.
+func renderGot(got error, at int) string {
+	if kind, ok := typedNil(got); ok {
+		return fmt.Sprintf("got non-nil error of type %T holding nil %s", got, kind)
+	}
+	if got == nil {
+		return fmt.Sprintf("got error %v", got)
+	}
+	msg, ok := message(got)
+	if !ok {
+		return fmt.Sprintf("got error of type %T whose %s", got, msg)
+	}
+	if len(msg) <= longMessage {
+		return fmt.Sprintf("got error %v", got)
+	}
+
+	at = min(max(at, 0), len(msg))
+	start := max(0, at-longMessageContext)
+	end := min(len(msg), start+2*longMessageContext)
+	start = max(0, end-2*longMessageContext)
+	for start > 0 && !utf8.RuneStart(msg[start]) {
+		start--
+	}
+	for end < len(msg) && !utf8.RuneStart(msg[end]) {
+		end++
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "got error of %d bytes:\n\t", len(msg))
+	if start > 0 {
+		fmt.Fprintf(&b, "…%d bytes elided…", start)
+	}
+	b.WriteString(strings.ReplaceAll(msg[start:end], "\n", "\n\t"))
+	if end < len(msg) {
+		fmt.Fprintf(&b, "…%d bytes elided…", len(msg)-end)
+	}
+	return b.String()
+}
+
+// nearestPartialMatch returns the index, in `s`, of the longest prefix of
+// `substr` found in `s`, or 0 if there is no such non-empty prefix.
+func nearestPartialMatch(s, substr string) int {
+	// If a prefix of length n is found, so are all shorter prefixes, so binary
+	// search over the length.
+	lo, hi, at := 1, len(substr), 0
+	for lo <= hi {
+		n := (lo + hi) / 2
+		if i := strings.Index(s, substr[:n]); i != -1 {
+			lo, at = n+1, i
+		} else {
+			hi = n - 1
+		}
+	}
+	return at
+}
+
+// message returns the `err.Error()` and true or, if the method panics or calls
+// [runtime.Goexit], a description of the failure and false. A Goexit can't be
+// stopped once started, let alone recovered from, so the method is called on a
+// goroutine of its own that is free to exit.
+func message(err error) (string, bool) {
+	c := &messageCall{err: err}
+	c.wg.Add(1)
+	go c.run()
+	c.wg.Wait()
+	if !c.returned {
+		return "Error() called runtime.Goexit()", false
+	}
+	return c.msg, c.ok
+}
+
+// A messageCall is a single call to [callError] by [message].
+type messageCall struct {
+	wg           sync.WaitGroup
+	err          error
+	msg          string
+	ok, returned bool
+}
+
+func (c *messageCall) run() {
+	defer c.wg.Done()
+	c.msg, c.ok = callError(c.err)
+	c.returned = true
+}
+
+// callError is equivalent to [message] except that it doesn't intercept
+// [runtime.Goexit].
+func callError(err error) (msg string, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg, ok = fmt.Sprintf("Error() panicked: %v", r), false
+		}
+	}()
+	return err.Error(), true
+}
+
+// quoteMessage returns the [message] of `err` formatted with %q or, if it
+// failed, a description of the failure in angle brackets.
+func quoteMessage(err error) string {
+	msg, ok := message(err)
+	if !ok {
+		return fmt.Sprintf("<%T whose %s>", err, msg)
+	}
+	return fmt.Sprintf("%q", msg)
+}
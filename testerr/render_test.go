@@ -0,0 +1,335 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+func TestLongMessages(t *testing.T) {
+	// A 4KiB message of the form aaaa…aaaNEEDLEbbbb…bbb
+	long := errors.New(strings.Repeat("a", 2000) + "NEEDLE" + strings.Repeat("b", 2090))
+	multiLine := errors.New(strings.Repeat("line\n", 300))
+
+	tests := []struct {
+		name                                 string
+		err                                  error
+		want                                 testerr.Want
+		wantPrefix, wantContains, wantSuffix string
+	}{
+		{
+			name:       "exactly at threshold",
+			err:        errors.New(strings.Repeat("x", 1024)),
+			want:       testerr.Is(errors.New("other")),
+			wantPrefix: "got error " + strings.Repeat("x", 1024) + "; want",
+		},
+		{
+			name:       "beginning by default",
+			err:        long,
+			want:       testerr.Is(errors.New("other")),
+			wantPrefix: "got error of 4096 bytes:\n\t" + strings.Repeat("a", 256) + "…3840 bytes elided…; want error that Is() other",
+		},
+		{
+			name:         "Contains() centres on nearest partial match",
+			err:          long,
+			want:         testerr.Contains("NEEDLES"),
+			wantPrefix:   "got error of 4096 bytes:\n\t…1872 bytes elided…" + strings.Repeat("a", 128) + "NEEDLE",
+			wantContains: "…1968 bytes elided…",
+			wantSuffix:   `; want containing substring "NEEDLES"`,
+		},
+		{
+			name:       "Contains() without partial match",
+			err:        long,
+			want:       testerr.Contains("xyz"),
+			wantPrefix: "got error of 4096 bytes:\n\t" + strings.Repeat("a", 256) + "…3840 bytes elided…",
+		},
+		{
+			name:       "MessageIs() centres on divergence",
+			err:        long,
+			want:       testerr.MessageIs(strings.Repeat("a", 2000) + "NEEDLE" + strings.Repeat("c", 2090)),
+			wantPrefix: "got error of 4096 bytes:\n\t…1878 bytes elided…" + strings.Repeat("a", 122) + "NEEDLE" + strings.Repeat("b", 128) + "…1962 bytes elided…; want message",
+			wantSuffix: `first difference at byte 2006: got …"aaaaaaaaaaNEEDLEbbbbbbbbbbbbbbbb"…; want …"aaaaaaaaaaNEEDLEcccccccccccccccc"…`,
+		},
+		{
+			name:       "multi-line indentation",
+			err:        multiLine,
+			want:       testerr.Contains("word"),
+			wantPrefix: "got error of 1500 bytes:\n\tline\n\tline\n\t",
+			wantSuffix: "…1244 bytes elided…; want containing substring \"word\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := testerr.Diff(tt.err, tt.want)
+			if !strings.HasPrefix(diff, tt.wantPrefix) {
+				t.Errorf("Diff(<long error>, …) got %q; want prefix %q", diff, tt.wantPrefix)
+			}
+			if !strings.Contains(diff, tt.wantContains) {
+				t.Errorf("Diff(<long error>, …) got %q; want containing %q", diff, tt.wantContains)
+			}
+			if !strings.HasSuffix(diff, tt.wantSuffix) {
+				t.Errorf("Diff(<long error>, …) got %q; want suffix %q", diff, tt.wantSuffix)
+			}
+		})
+	}
+}
+
+// verboseError implements [fmt.Formatter] to include more detail with %+v.
+type verboseError struct{}
+
+func (verboseError) Error() string { return "verbose" }
+
+func (e verboseError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprint(s, "verbose [with detail]")
+		return
+	}
+	fmt.Fprint(s, e.Error())
+}
+
+func ExampleDiffOpts() {
+	errTrailing := errors.New("not found ")
+	want := testerr.MessageIs("not found")
+
+	for _, opts := range [][]testerr.DiffOption{
+		nil,
+		{testerr.Quote()},
+		{testerr.Quote(), testerr.ShowType()},
+	} {
+		fmt.Println(testerr.DiffOpts(errTrailing, want, opts...))
+	}
+
+	fmt.Println("---")
+	notEOF := testerr.Is(io.EOF)
+	fmt.Println(testerr.DiffOpts(verboseError{}, notEOF))
+	fmt.Println(testerr.DiffOpts(verboseError{}, notEOF, testerr.Verbose()))
+	fmt.Println(testerr.DiffOpts(verboseError{}, notEOF, testerr.Verbose(), testerr.Quote()))
+
+	// Output:
+	// got error not found ; want message "not found"; first difference at byte 9: got "not found "; want "not found"
+	// got error "not found "; want message "not found"; first difference at byte 9: got "not found "; want "not found"
+	// got error "not found " of type *errors.errorString; want message "not found"; first difference at byte 9: got "not found "; want "not found"
+	// ---
+	// got error verbose; want error that Is() EOF
+	// got error verbose [with detail]; want error that Is() EOF
+	// got error "verbose [with detail]"; want error that Is() EOF
+}
+
+func TestDiffOpts(t *testing.T) {
+	errTrailing := errors.New("uh oh ")
+	long := errors.New(strings.Repeat("x", 2048))
+
+	tests := []struct {
+		name string
+		got  error
+		want testerr.Want
+		opts []testerr.DiffOption
+		diff string
+	}{
+		{
+			name: "matched",
+			got:  errTrailing,
+			want: testerr.Contains("uh"),
+			opts: []testerr.DiffOption{testerr.Quote()},
+			diff: "",
+		},
+		{
+			name: "nested diffs",
+			got:  errTrailing,
+			want: testerr.All(testerr.Is(io.EOF), testerr.MessageIs("uh oh")),
+			opts: []testerr.DiffOption{testerr.Quote()},
+			diff: `got error "uh oh "; want all of 2 expectations; 2 failed:` + "\n" +
+				`	[0] got error "uh oh "; want error that Is() EOF` + "\n" +
+				`	[1] got error "uh oh "; want message "uh oh"; first difference at byte 5: got "uh oh "; want "uh oh"`,
+		},
+		{
+			name: "other errors with same prefix untouched",
+			got:  errors.New("a"),
+			want: testerr.Map(
+				func(error) (error, string) { return errors.New("a; b"), "derivation" },
+				testerr.Is(io.EOF),
+			),
+			opts: []testerr.DiffOption{testerr.Quote()},
+			diff: `got error "a"; want derivation to yield error that Is() EOF:` + "\n" +
+				`	got error a; b; want error that Is() EOF`,
+		},
+		{
+			name: "nil error",
+			got:  nil,
+			want: testerr.Is(io.EOF),
+			opts: []testerr.DiffOption{testerr.Quote(), testerr.ShowType()},
+			diff: "got error <nil>; want error that Is() EOF",
+		},
+		{
+			name: "long message",
+			got:  long,
+			want: testerr.Is(io.EOF),
+			opts: []testerr.DiffOption{testerr.Quote(), testerr.ShowType()},
+			diff: "got error of type *errors.errorString, 2048 bytes:\n\t" + strings.Repeat("x", 256) + "…1792 bytes elided…; want error that Is() EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testerr.DiffOpts(tt.got, tt.want, tt.opts...); got != tt.diff {
+				t.Errorf("DiffOpts(%.16v, %s, …) got %q; want %q", tt.got, testerr.Describe(tt.want), got, tt.diff)
+			}
+		})
+	}
+}
+
+// panickingError panics, with a non-error value, when its message is requested.
+type panickingError struct{}
+
+func (panickingError) Error() string { panic("boom") }
+
+// embeddedNilError has a nil embedded pointer, so its promoted Error() method
+// dereferences nil.
+type embeddedNilError struct{ *nilReceiverError }
+
+// goexitError calls runtime.Goexit when its message is requested, as would
+// t.FailNow().
+type goexitError struct{}
+
+func (goexitError) Error() string {
+	runtime.Goexit()
+	return "unreachable"
+}
+
+// nilReceiverError dereferences its receiver, which is nil in the test.
+type nilReceiverError struct{ msg string }
+
+func (e *nilReceiverError) Error() string { return e.msg }
+
+func TestErrorMethodFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantRender string
+	}{
+		{
+			name:       "panic with non-error value",
+			err:        panickingError{},
+			wantRender: "got error of type testerr_test.panickingError whose Error() panicked: boom",
+		},
+		{
+			name:       "nil embedded pointer",
+			err:        embeddedNilError{},
+			wantRender: "got error of type testerr_test.embeddedNilError whose Error() panicked: runtime error: invalid memory address or nil pointer dereference",
+		},
+		{
+			name:       "runtime.Goexit",
+			err:        goexitError{},
+			wantRender: "got error of type testerr_test.goexitError whose Error() called runtime.Goexit()",
+		},
+		{
+			name:       "nil receiver in tree",
+			err:        errors.Join((*nilReceiverError)(nil)),
+			wantRender: "got error of type *errors.joinError whose Error() panicked: runtime error: invalid memory address or nil pointer dereference",
+		},
+	}
+
+	wants := []testerr.Want{
+		testerr.Is(io.EOF),
+		testerr.Contains("x"),
+		testerr.MessageIs("x"),
+		testerr.HasPrefix("x"),
+		testerr.MatchesRegexp("x"),
+		testerr.ContainsAll("x"),
+		testerr.ContainsAny("x"),
+		testerr.ContainsFold("x"),
+		testerr.MatchesFormat("x"),
+		testerr.JSONMessage(nil),
+		testerr.Normalize(testerr.Contains("x")),
+		testerr.CountMatching(testerr.Contains("x"), 1),
+		testerr.ChainOf(testerr.Contains("x"), testerr.Contains("x")),
+		testerr.Any(testerr.MessageIs("x"), testerr.HasSuffix("x")),
+		testerr.Any(testerr.Contains("x")),
+		testerr.All(testerr.Contains("x")),
+		testerr.Named("named", testerr.Contains("x")),
+		testerr.Not(testerr.Not(testerr.Contains("x"))),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, w := range wants {
+				diff := testerr.Diff(tt.err, w)
+				if !strings.HasPrefix(diff, tt.wantRender+"; want ") {
+					t.Errorf("Diff(<%T>, %s) got %q; want prefix %q", tt.err, testerr.Describe(w), diff, tt.wantRender)
+				}
+				if testerr.Matches(tt.err, w) {
+					t.Errorf("Matches(<%T>, %s) got true; want false", tt.err, testerr.Describe(w))
+				}
+			}
+
+			// A message that can't be obtained never matches so its negation
+			// always does.
+			not := testerr.Not(testerr.Contains("x"))
+			if diff := testerr.Diff(tt.err, not); diff != "" {
+				t.Errorf("Diff(<%T>, %s) got %q; want empty", tt.err, testerr.Describe(not), diff)
+			}
+			if !testerr.Matches(tt.err, not) {
+				t.Errorf("Matches(<%T>, %s) got false; want true", tt.err, testerr.Describe(not))
+			}
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		err    error
+		wantOK bool
+		want   string
+	}{
+		{err: io.EOF, wantOK: true, want: "EOF"},
+		{err: nil, wantOK: false, want: ""},
+		{err: panickingError{}, wantOK: false, want: "Error() panicked: boom"},
+		{err: goexitError{}, wantOK: false, want: "Error() called runtime.Goexit()"},
+	}
+
+	for _, tt := range tests {
+		got, ok := testerr.Message(tt.err)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Message(<%T>) got (%q, %t); want (%q, %t)", tt.err, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+// formattedError has a message that differs from its %v rendering.
+type formattedError struct{}
+
+func (formattedError) Error() string { return "plain" }
+
+func (formattedError) Format(s fmt.State, verb rune) { fmt.Fprint(s, "formatted") }
+
+func TestRenderGotFormatter(t *testing.T) {
+	const want = "got error formatted; want error that Is() EOF"
+	if got := testerr.Diff(formattedError{}, testerr.Is(io.EOF)); got != want {
+		t.Errorf("Diff(formattedError{}, Is(io.EOF)) got %q; want %q", got, want)
+	}
+}
+
+func TestErrorMethodFailuresInTree(t *testing.T) {
+	err := fmt.Errorf("outer: %w", errors.Join(io.EOF, panickingError{}))
+
+	diff := testerr.DiffVerbose(err, testerr.Contains("x"))
+	if want := "testerr_test.panickingError <testerr_test.panickingError whose Error() panicked: boom>"; !strings.Contains(diff, want) {
+		t.Errorf("DiffVerbose(<tree with panicking node>, …) got %q; want containing %q", diff, want)
+	}
+
+	join := errors.Join(io.EOF, panickingError{})
+	diff = testerr.Diff(join, testerr.Joined(testerr.Is(io.EOF), testerr.Contains("x")))
+	if want := "[1] got error of type testerr_test.panickingError whose Error() panicked: boom; want "; !strings.Contains(diff, want) {
+		t.Errorf("Diff(<join with panicking component>, Joined(…)) got %q; want containing %q", diff, want)
+	}
+
+	if diff := testerr.Diff(err, testerr.Is(io.EOF)); diff != "" {
+		t.Errorf("Diff(<tree with panicking node>, Is(io.EOF)) got %q; want empty as Error() isn't needed", diff)
+	}
+}
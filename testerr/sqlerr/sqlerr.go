@@ -0,0 +1,60 @@
+// Package sqlerr provides [testerr.Want] implementations for errors returned
+// by [database/sql] and by database drivers. Driver errors are matched by the
+// methods that they expose, rather than by their types, so the package doesn't
+// depend on any driver.
+package sqlerr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// NoRows checks that the `got` error [errors.Is] [sql.ErrNoRows]. If it isn't,
+// but its message includes that of sql.ErrNoRows, the diff notes that the
+// error was likely re-created without wrapping, e.g. with %v instead of %w.
+func NoRows() testerr.Want {
+	desc := "error that Is() sql.ErrNoRows"
+	return testerr.DescribedFunc(desc, func(got error) string {
+		if errors.Is(got, sql.ErrNoRows) {
+			return ""
+		}
+		if msg, ok := testerr.Message(got); ok && strings.Contains(msg, sql.ErrNoRows.Error()) {
+			return testerr.DiffMessage(got, "%s; message includes %q but sql.ErrNoRows isn't wrapped, so was likely swallowed and re-created", desc, sql.ErrNoRows)
+		}
+		return testerr.DiffMessage(got, "%s", desc)
+	})
+}
+
+// SQLState checks that a node in the `got` error's tree has a `SQLState()
+// string` method, as found by [errors.As], that returns `code` (e.g. "23505"
+// for a unique violation). Such methods are exposed by the errors of some
+// PostgreSQL drivers. If no node has the method, the diff lists the types in
+// the tree.
+func SQLState(code string) testerr.Want {
+	return testerr.Implements(func(e interface{ SQLState() string }) string {
+		if got := e.SQLState(); got != code {
+			return fmt.Sprintf("SQLState() %q; got %q", code, got)
+		}
+		return ""
+	})
+}
+
+// ConstraintViolation checks that a node in the `got` error's tree has a
+// `ConstraintName() string` method, as found by [errors.As], the result of
+// which matches `nameWant`. The name is checked with [testerr.Diff] as an error
+// of which it is the string, so a nil `nameWant` never matches and
+// `testerr.Contains("")` SHOULD be used to accept any constraint. If no node
+// has the method, the diff lists the types in the tree. Drivers that expose the
+// name as a field can be adapted with [testerr.Map].
+func ConstraintViolation(nameWant testerr.Want) testerr.Want {
+	return testerr.Implements(func(e interface{ ConstraintName() string }) string {
+		if d := testerr.Diff(errors.New(e.ConstraintName()), nameWant); d != "" {
+			return "ConstraintName(): " + d
+		}
+		return ""
+	})
+}
@@ -0,0 +1,166 @@
+package sqlerr_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+	"github.com/arr4n/shed/testerr/sqlerr"
+)
+
+// pgError mimics the error type of a PostgreSQL driver.
+type pgError struct {
+	code, constraint string
+}
+
+func (e *pgError) Error() string          { return fmt.Sprintf("pg error %s", e.code) }
+func (e *pgError) SQLState() string       { return e.code }
+func (e *pgError) ConstraintName() string { return e.constraint }
+
+// goexitError calls runtime.Goexit when its message is requested, as would
+// t.FailNow().
+type goexitError struct{}
+
+func (goexitError) Error() string {
+	runtime.Goexit()
+	return "unreachable"
+}
+
+type user struct{ name string }
+
+// userRepo is a repository, the methods of which wrap database errors.
+type userRepo struct {
+	users map[int]user
+	err   error
+}
+
+func (r *userRepo) get(id int) (user, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return user{}, fmt.Errorf("get user %d: %w", id, sql.ErrNoRows)
+	}
+	return u, nil
+}
+
+func (r *userRepo) create(u user) error {
+	if r.err != nil {
+		return fmt.Errorf("create user %q: %w", u.name, r.err)
+	}
+	return nil
+}
+
+func ExampleSQLState() {
+	repo := &userRepo{
+		err: &pgError{code: "23505", constraint: "users_name_key"},
+	}
+	err := repo.create(user{name: "arr4n"})
+
+	for _, want := range []testerr.Want{
+		sqlerr.SQLState("23505"),
+		sqlerr.ConstraintViolation(testerr.MessageIs("users_name_key")),
+		sqlerr.SQLState("23503"),
+	} {
+		if diff := testerr.Diff(err, want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// <empty>
+	// got error create user "arr4n": pg error 23505; want error tree containing implementation of interface { SQLState() string }; found *sqlerr_test.pgError but check failed: SQLState() "23503"; got "23505"
+}
+
+func TestSQLErr(t *testing.T) {
+	repo := &userRepo{users: map[int]user{1: {"a"}}}
+	_, missing := repo.get(2)
+	swallowed := fmt.Errorf("get user 2: %v", sql.ErrNoRows)
+	unique := fmt.Errorf("insert: %w", &pgError{code: "23505", constraint: "users_email_key"})
+
+	tests := []struct {
+		name     string
+		err      error
+		want     testerr.Want
+		wantDiff string // following "want"; empty if no diff is expected
+	}{
+		{
+			name: "NoRows() through repository",
+			err:  missing,
+			want: sqlerr.NoRows(),
+		},
+		{
+			name:     "NoRows() swallowed",
+			err:      swallowed,
+			want:     sqlerr.NoRows(),
+			wantDiff: `error that Is() sql.ErrNoRows; message includes "sql: no rows in result set" but sql.ErrNoRows isn't wrapped, so was likely swallowed and re-created`,
+		},
+		{
+			name:     "NoRows() of other error",
+			err:      unique,
+			want:     sqlerr.NoRows(),
+			wantDiff: "error that Is() sql.ErrNoRows",
+		},
+		{
+			name:     "NoRows() of nil",
+			want:     sqlerr.NoRows(),
+			wantDiff: "error that Is() sql.ErrNoRows",
+		},
+		{
+			name:     "NoRows() of error with Goexit-ing Error()",
+			err:      goexitError{},
+			want:     sqlerr.NoRows(),
+			wantDiff: "error that Is() sql.ErrNoRows",
+		},
+		{
+			name: "SQLState()",
+			err:  unique,
+			want: sqlerr.SQLState("23505"),
+		},
+		{
+			name:     "SQLState() mismatch",
+			err:      unique,
+			want:     sqlerr.SQLState("40001"),
+			wantDiff: `error tree containing implementation of interface { SQLState() string }; found *sqlerr_test.pgError but check failed: SQLState() "40001"; got "23505"`,
+		},
+		{
+			name:     "SQLState() without accessor",
+			err:      missing,
+			want:     sqlerr.SQLState("23505"),
+			wantDiff: "error tree containing implementation of interface { SQLState() string }; found types [*fmt.wrapError, *errors.errorString]",
+		},
+		{
+			name: "ConstraintViolation()",
+			err:  unique,
+			want: sqlerr.ConstraintViolation(testerr.HasPrefix("users_")),
+		},
+		{
+			name:     "ConstraintViolation() mismatch",
+			err:      unique,
+			want:     sqlerr.ConstraintViolation(testerr.MessageIs("users_name_key")),
+			wantDiff: `error tree containing implementation of interface { ConstraintName() string }; found *sqlerr_test.pgError but check failed: ConstraintName(): got error users_email_key; want message "users_name_key"; first difference at byte 6: got "users_email_key"; want "users_name_key"`,
+		},
+		{
+			name:     "ConstraintViolation() without accessor",
+			err:      errors.Join(missing, swallowed),
+			want:     sqlerr.ConstraintViolation(testerr.Contains("")),
+			wantDiff: "error tree containing implementation of interface { ConstraintName() string }; found types [*errors.joinError, *fmt.wrapError, *errors.errorString]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want string
+			if tt.wantDiff != "" {
+				want = testerr.DiffMessage(tt.err, "%s", tt.wantDiff)
+			}
+			if diff := testerr.Diff(tt.err, tt.want); diff != want {
+				t.Errorf("Diff(%v, %s) got %q; want %q", tt.err, testerr.Describe(tt.want), diff, want)
+			}
+		})
+	}
+}
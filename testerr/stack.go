@@ -0,0 +1,192 @@
+package testerr
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// stackFramesShown is the maximum number of frames, per stack trace, included
+// in diffs by [StackContains].
+const stackFramesShown = 5
+
+// HasStack checks that a node in the `got` error's tree exposes a stack trace
+// via a `StackTrace()` method returning a slice of program counters, of which
+// `github.com/pkg/errors.StackTrace` is an example. The method is found by
+// reflection, so any type whose elements are of kind [reflect.Uintptr]
+// suffices and this package doesn't depend on any such module. As with
+// pkg/errors, each program counter is assumed to be a return address, as
+// returned by [runtime.Callers].
+func HasStack() Want {
+	desc := "error tree containing a StackTrace()"
+	return &described{
+		desc:    desc,
+		details: treeTypesDetails,
+		match:   hasStack,
+		diff: func(got error) string {
+			if hasStack(got) {
+				return ""
+			}
+			return typeDiff(got, desc)
+		},
+	}
+}
+
+// StackContains checks that a frame, of any stack trace found as by
+// [HasStack], is of a function the fully qualified name of which contains
+// `funcSubstr`; e.g. "mypkg.(*Server).handle". On mismatch, the diff lists the
+// top frames of every stack trace in the tree.
+func StackContains(funcSubstr string) Want {
+	desc := fmt.Sprintf("error tree containing a StackTrace() with function %q", funcSubstr)
+	return &described{
+		desc:    desc,
+		details: treeTypesDetails,
+		match:   func(got error) bool { return stackContains(got, funcSubstr) },
+		diff: func(got error) string {
+			if stackContains(got, funcSubstr) {
+				return ""
+			}
+			stacks := stackTraces(got)
+			if len(stacks) == 0 {
+				return typeDiff(got, desc)
+			}
+			var found []string
+			for _, st := range stacks {
+				if st.contains(funcSubstr) {
+					return ""
+				}
+				found = append(found, "\t"+st.String())
+			}
+			return DiffMessage(got, "%s; found:\n%s", desc, strings.Join(found, "\n"))
+		},
+	}
+}
+
+// A stackTrace is the function names of the frames of a stack trace exposed by
+// a node of an error tree.
+type stackTrace struct {
+	node  error
+	funcs []string
+}
+
+func (st stackTrace) contains(substr string) bool {
+	for _, f := range st.funcs {
+		if strings.Contains(f, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the node's type and its top [stackFramesShown] frames.
+func (st stackTrace) String() string {
+	funcs := st.funcs
+	var more string
+	if n := len(funcs) - stackFramesShown; n > 0 {
+		funcs = funcs[:stackFramesShown]
+		more = fmt.Sprintf(" …and %d more", n)
+	}
+	return fmt.Sprintf("%T: [%s]%s", st.node, strings.Join(funcs, ", "), more)
+}
+
+// hasStack reports whether any node in the error tree has a StackTrace()
+// method, without calling it.
+func hasStack(err error) bool {
+	var found bool
+	Walk(err, func(node error) bool {
+		if _, ok := typedNil(node); !ok {
+			found = hasStackMethod(reflect.TypeOf(node))
+		}
+		return !found
+	})
+	return found
+}
+
+// stackMethods caches the results of [hasStackMethod] as the lookup of methods
+// by name allocates.
+var stackMethods sync.Map // reflect.Type -> bool
+
+// hasStackMethod reports whether `t` has a StackTrace() method of the form
+// described by [HasStack].
+func hasStackMethod(t reflect.Type) bool {
+	if ok, cached := stackMethods.Load(t); cached {
+		return ok.(bool)
+	}
+	m, ok := t.MethodByName("StackTrace")
+	ok = ok && isStackTraceMethod(m.Type)
+	stackMethods.Store(t, ok)
+	return ok
+}
+
+// stackContains is equivalent to checking [stackTrace.contains] for all
+// [stackTraces], but without collecting function names.
+func stackContains(err error, funcSubstr string) bool {
+	var found bool
+	Walk(err, func(node error) bool {
+		pcs, ok := stackPCs(node)
+		for i := 0; ok && !found && i < len(pcs); i++ {
+			found = strings.Contains(funcName(pcs[i]), funcSubstr)
+		}
+		return !found
+	})
+	return found
+}
+
+// stackTraces returns the stack traces of all nodes in the error tree, in the
+// order of [walk].
+func stackTraces(err error) []stackTrace {
+	var stacks []stackTrace
+	walk(err, func(node error) {
+		if pcs, ok := stackPCs(node); ok {
+			stacks = append(stacks, stackTrace{node, funcNames(pcs)})
+		}
+	})
+	return stacks
+}
+
+// stackPCs returns the result of the `StackTrace()` method of `node`, if it has
+// one of the form described by [HasStack]. Typed nils are ignored to avoid the
+// method panicking.
+func stackPCs(node error) ([]uintptr, bool) {
+	if _, ok := typedNil(node); ok {
+		return nil, false
+	}
+	if !hasStackMethod(reflect.TypeOf(node)) {
+		return nil, false
+	}
+
+	st := reflect.ValueOf(node).MethodByName("StackTrace").Call(nil)[0]
+	pcs := make([]uintptr, st.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(st.Index(i).Uint())
+	}
+	return pcs, true
+}
+
+// funcNames returns the names of the functions containing the return addresses.
+func funcNames(pcs []uintptr) []string {
+	names := make([]string, len(pcs))
+	for i, pc := range pcs {
+		names[i] = funcName(pc)
+	}
+	return names
+}
+
+// funcName returns the name of the function of the return address `pc`, or
+// "unknown".
+func funcName(pc uintptr) string {
+	// As with runtime.Frames, the return address is after the call.
+	if fn := runtime.FuncForPC(pc - 1); fn != nil {
+		return fn.Name()
+	}
+	return "unknown"
+}
+
+// isStackTraceMethod reports whether `t`, the type of a [reflect.Method]
+// including its receiver, is that of a method of the form described by
+// [HasStack].
+func isStackTraceMethod(t reflect.Type) bool {
+	return t.NumIn() == 1 && t.NumOut() == 1 && t.Out(0).Kind() == reflect.Slice && t.Out(0).Elem().Kind() == reflect.Uintptr
+}
@@ -0,0 +1,157 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// frame and stackTrace mirror the types of the same names in
+// github.com/pkg/errors.
+type (
+	frame      uintptr
+	stackTrace []frame
+)
+
+// stackError records the stack of its creator, like the errors of
+// github.com/pkg/errors.
+type stackError struct {
+	error
+	stack []uintptr
+}
+
+func (e *stackError) Unwrap() error { return e.error }
+
+func (e *stackError) StackTrace() stackTrace {
+	st := make(stackTrace, len(e.stack))
+	for i, pc := range e.stack {
+		st[i] = frame(pc)
+	}
+	return st
+}
+
+//go:noinline
+func withStack(err error) error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &stackError{err, pcs[:n]}
+}
+
+//go:noinline
+func originHelper() error {
+	return withStack(io.EOF)
+}
+
+//go:noinline
+func middlewareHelper(err error) error {
+	return withStack(fmt.Errorf("middleware: %w", err))
+}
+
+func TestStack(t *testing.T) {
+	origin := originHelper()
+	wrapped := fmt.Errorf("handler: %w", origin)
+	rewrapped := middlewareHelper(errors.New("origin lost"))
+
+	tests := []struct {
+		name     string
+		err      error
+		want     testerr.Want
+		wantDiff string // following "want"; empty if no diff is expected
+		// prefixOnly limits the check of the diff to the prefix `wantDiff`, as
+		// frames below the test function belong to the testing package.
+		prefixOnly bool
+	}{
+		{
+			name: "HasStack() wrapped",
+			err:  wrapped,
+			want: testerr.HasStack(),
+		},
+		{
+			name:     "HasStack() without stack",
+			err:      fmt.Errorf("x: %w", io.EOF),
+			want:     testerr.HasStack(),
+			wantDiff: "error tree containing a StackTrace(); found types [*fmt.wrapError, *errors.errorString]",
+		},
+		{
+			name:     "HasStack() of nil",
+			want:     testerr.HasStack(),
+			wantDiff: "error tree containing a StackTrace()",
+		},
+		{
+			name: "StackContains() helper",
+			err:  wrapped,
+			want: testerr.StackContains("testerr_test.originHelper"),
+		},
+		{
+			name: "StackContains() caller of helper",
+			err:  wrapped,
+			want: testerr.StackContains("TestStack"),
+		},
+		{
+			name: "StackContains() any of multiple stacks",
+			err:  middlewareHelper(origin),
+			want: testerr.StackContains("originHelper"),
+		},
+		{
+			name:       "StackContains() wrong layer",
+			err:        rewrapped,
+			want:       testerr.StackContains("originHelper"),
+			wantDiff:   "error tree containing a StackTrace() with function \"originHelper\"; found:\n\t*testerr_test.stackError: [github.com/arr4n/shed/testerr_test.middlewareHelper, github.com/arr4n/shed/testerr_test.TestStack, ",
+			prefixOnly: true,
+		},
+		{
+			name:     "StackContains() without stack",
+			err:      io.EOF,
+			want:     testerr.StackContains("x"),
+			wantDiff: `error tree containing a StackTrace() with function "x"; found types [*errors.errorString]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.prefixOnly {
+				checkDiff(t, tt.err, tt.want, tt.wantDiff)
+				return
+			}
+			want := testerr.DiffMessage(tt.err, "%s", tt.wantDiff)
+			if diff := testerr.Diff(tt.err, tt.want); !strings.HasPrefix(diff, want) {
+				t.Errorf("Diff(%v, %s) got %q; want prefix %q", tt.err, testerr.Describe(tt.want), diff, want)
+			}
+			if testerr.Matches(tt.err, tt.want) {
+				t.Errorf("Matches(%v, %s) got true; want false", tt.err, testerr.Describe(tt.want))
+			}
+		})
+	}
+}
+
+// badStackTrace has a StackTrace() method of an unsupported form.
+type badStackTrace struct{}
+
+func (badStackTrace) Error() string        { return "bad" }
+func (badStackTrace) StackTrace() []string { return []string{"x"} }
+
+func TestStackUnsupported(t *testing.T) {
+	if diff := testerr.Diff(badStackTrace{}, testerr.HasStack()); diff == "" {
+		t.Error("Diff(<StackTrace() []string>, HasStack()) got empty diff; want non-empty")
+	}
+}
+
+func TestStackFramesShown(t *testing.T) {
+	var deep func(int) error
+	deep = func(n int) error {
+		if n == 0 {
+			return withStack(io.EOF)
+		}
+		return deep(n - 1)
+	}
+	diff := testerr.Diff(deep(10), testerr.StackContains("nowhere"))
+	if !regexp.MustCompile(`\] …and \d+ more$`).MatchString(diff) {
+		t.Errorf("Diff(<deep stack>, StackContains(…)) got %q; want frames truncated", diff)
+	}
+}
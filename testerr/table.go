@@ -0,0 +1,40 @@
+package testerr
+
+import "testing"
+
+// A Case is a single test case for [RunTable].
+type Case[I any] struct {
+	// Name is used as the name of the subtest.
+	Name string
+	// Input is passed to the function under test.
+	Input I
+	// Want is checked against the returned error, treated in the same manner
+	// as by [Diff] so a nil value expects a nil error.
+	Want Want
+	// Parallel, if true, results in the subtest calling [testing.T.Parallel].
+	Parallel bool
+}
+
+// RunTable runs every case as a subtest named by [Case.Name], in which `fn` is
+// called with [Case.Input] and the returned error is checked against
+// [Case.Want], reporting any diff via `t.Errorf()`. It is therefore
+// equivalent to the common, hand-written loop over a table of cases, calling
+// `t.Run()` for each.
+//
+// As with any parallel subtests, those of cases marked [Case.Parallel] only
+// complete after the calling test function returns, so RunTable SHOULD be the
+// last statement in the test or wrapped in its own `t.Run()`.
+func RunTable[I any](t *testing.T, cases []Case[I], fn func(*testing.T, I) error) {
+	t.Helper()
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			t.Helper()
+			if c.Parallel {
+				t.Parallel()
+			}
+			if diff := Diff(fn(t, c.Input), c.Want); diff != "" {
+				t.Errorf("%s", diff)
+			}
+		})
+	}
+}
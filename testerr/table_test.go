@@ -0,0 +1,116 @@
+package testerr_test
+
+import (
+	"errors"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+func TestRunTableSequential(t *testing.T) {
+	var order []int
+	t.Run("table", func(t *testing.T) {
+		testerr.RunTable(t, []testerr.Case[int]{
+			{Name: "zero", Input: 0, Want: nil},
+			{Name: "one", Input: 1, Want: testerr.Is(io.EOF)},
+			{Name: "two", Input: 2, Want: testerr.Contains("2")},
+		}, func(t *testing.T, i int) error {
+			order = append(order, i)
+			switch i {
+			case 0:
+				return nil
+			case 1:
+				return io.EOF
+			default:
+				return errors.New(strconv.Itoa(i))
+			}
+		})
+	})
+
+	if want := []int{0, 1, 2}; !slices.Equal(order, want) {
+		t.Errorf("RunTable() called function with inputs %v; want %v", order, want)
+	}
+}
+
+func TestRunTableParallel(t *testing.T) {
+	// Parallel subtests are paused until their parent's function returns, so
+	// only they will observe `returned` as true.
+	var (
+		mu       sync.Mutex
+		returned bool
+		got      = make(map[string]bool)
+	)
+	cases := []testerr.Case[string]{
+		{Name: "sequential", Input: "sequential"},
+		{Name: "parallel_0", Input: "parallel_0", Parallel: true},
+		{Name: "parallel_1", Input: "parallel_1", Parallel: true},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		testerr.RunTable(t, cases, func(t *testing.T, name string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			got[name] = returned
+			return nil
+		})
+		mu.Lock()
+		returned = true
+		mu.Unlock()
+	})
+
+	want := map[string]bool{
+		"sequential": false,
+		"parallel_0": true,
+		"parallel_1": true,
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("RunTable() function calls observing parent return %v; want %v", got, want)
+	}
+}
+
+const runTableFailuresEnv = "TESTERR_RUN_TABLE_FAILURES"
+
+// TestRunTableFailures is a deliberately failing test, only run as a
+// subprocess of [TestRunTableAttribution].
+func TestRunTableFailures(t *testing.T) {
+	if os.Getenv(runTableFailuresEnv) == "" {
+		t.Skip("only run as a subprocess")
+	}
+	testerr.RunTable(t, []testerr.Case[error]{
+		{Name: "passes", Input: io.EOF, Want: testerr.Is(io.EOF)},
+		{Name: "fails", Input: io.ErrUnexpectedEOF, Want: testerr.Is(io.EOF), Parallel: true},
+	}, func(_ *testing.T, err error) error { return err })
+}
+
+func TestRunTableAttribution(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs test binary in a subprocess")
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRunTableFailures$", "-test.v")
+	cmd.Env = append(os.Environ(), runTableFailuresEnv+"=1")
+	out, err := cmd.CombinedOutput()
+
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) {
+		t.Fatalf("%s error = %v; want %T\n%s", cmd, err, exit, out)
+	}
+
+	for _, re := range []string{
+		`(?m)^    --- PASS: TestRunTableFailures/passes \(`,
+		`(?m)^    --- FAIL: TestRunTableFailures/fails \(`,
+		// The reported line is within the table test, not RunTable.
+		`(?m)^\s+table_test\.go:\d+: got error unexpected EOF; want error that Is\(\) EOF$`,
+	} {
+		if !regexp.MustCompile(re).Match(out) {
+			t.Errorf("%s output doesn't match %q:\n%s", cmd, re, out)
+		}
+	}
+}
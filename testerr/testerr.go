@@ -1,10 +1,16 @@
 // Package testerr provides mechanisms for specifying expected properties of
 // errors during testing.
+//
+// Every [Want] returned by this package is safe for concurrent use by multiple
+// goroutines and MAY be constructed once and reused, for example across table
+// rows, parallel subtests, or iterations of a fuzz target. Any work that can be
+// performed at construction is, so reuse doesn't incur repeated costs.
 package testerr
 
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -18,21 +24,117 @@ type Want interface {
 }
 
 // Diff compares the error with what is wanted. A nil [Want] corresponds to a
-// nil error.
+// nil error, as does a non-nil [Want] holding a nil pointer, func, or other
+// nillable value (a "typed nil"), which would otherwise likely panic. Tables
+// of test cases SHOULD prefer the explicit [Nil] to either. As diffs are only
+// constructed once a mismatch is established, a matching `got` error typically
+// results in no allocations other than the few needed by each call of its
+// Error() method; see [Matches].
 func Diff(got error, want Want) string {
-	if want == nil {
+	if isNil(want) {
 		if got == nil {
 			return ""
 		}
-		return DiffMessage(got, "nil")
+		return DiffMessage(got, "%s", Describe(nil))
 	}
 	return want.ErrDiff(got)
 }
 
+// Matches reports whether [Diff] of `got` and `want` is empty, including the
+// convention that a nil [Want] matches only a nil error. It is intended for use
+// outside of tests, such as in fuzz targets and retry predicates, and avoids
+// constructing diffs where possible, so matches typically don't allocate. The
+// exception is that every call to the `got` error's Error() method is made on a
+// new goroutine, so that a panic or [runtime.Goexit] is reported as a mismatch
+// instead of failing the caller.
+func Matches(got error, want Want) bool {
+	if isNil(want) {
+		return got == nil
+	}
+	if d, ok := want.(*described); ok && d.match != nil {
+		return d.match(got)
+	}
+	return want.ErrDiff(got) == ""
+}
+
+// MatchFunc returns a function that reports whether its argument [Matches]
+// `want`.
+func MatchFunc(want Want) func(error) bool {
+	return func(got error) bool { return Matches(got, want) }
+}
+
+// Nil returns a [Want] that matches only a nil error. It is equivalent to a
+// nil [Want] but more explicit.
+func Nil() Want {
+	return predicate(Describe(nil), func(got error) bool { return got == nil })
+}
+
+// isNil reports whether `w` is nil or a typed nil.
+func isNil(w Want) bool {
+	if w == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(w); v.Kind() {
+	case reflect.Pointer, reflect.Func, reflect.Map, reflect.Slice, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
 // DiffMessage constructs a canonical diff message for use in test failures.
+//
+// If `got` is a non-nil error holding a nil pointer (a "typed nil", e.g. a nil
+// `*MyErr` returned as an `error`), the diff says so, without calling its
+// `Error()` method, instead of the otherwise confusing "got error <nil>".
+//
+// Similarly, if the `Error()` method panics or calls [runtime.Goexit], the diff
+// names the error's concrete type and describes the failure, including any
+// panic value, instead of crashing the test. Matchers that inspect messages
+// treat such errors as mismatches.
+//
+// Messages longer than 1KiB are truncated to an excerpt from their beginning,
+// with the number of elided bytes noted. Matchers such as [Contains] and
+// [MessageIs] instead centre the excerpt on the most relevant region.
 func DiffMessage(got error, wantFormat string, a ...any) string {
-	format := fmt.Sprintf("got error %%v; want %s", wantFormat)
-	return fmt.Sprintf(format, append([]any{got}, a...)...)
+	return diffMessageAt(got, 0, wantFormat, a...)
+}
+
+// Message returns the `err.Error()` and true or, if the method panics or calls
+// [runtime.Goexit], a description of the failure and false. It is intended for
+// packages that extend this one, so that they inspect messages with the same
+// protection as the matchers herein. A nil error has no message.
+func Message(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+	return message(err)
+}
+
+// typedNil reports whether `err` is a non-nil interface holding a nil value,
+// along with a description of the value's kind.
+func typedNil(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+	switch v := reflect.ValueOf(err); v.Kind() {
+	case reflect.Pointer:
+		return "pointer", v.IsNil()
+	case reflect.Func, reflect.Map, reflect.Slice, reflect.Chan, reflect.Interface:
+		return v.Kind().String(), v.IsNil()
+	default:
+		return "", false
+	}
+}
+
+// NotTypedNil checks that the `got` error isn't a non-nil interface holding a
+// nil pointer or other nillable value; see [DiffMessage]. Both nil errors and
+// all other non-nil errors match.
+func NotTypedNil() Want {
+	return predicate("error that isn't a typed nil", func(got error) bool {
+		_, ok := typedNil(got)
+		return !ok
+	})
 }
 
 // A Func is an adaptor to convert an ordinary function into a [Want] by calling
@@ -44,14 +146,145 @@ func (fn Func) ErrDiff(got error) string {
 	return fn(got)
 }
 
+// DescribedFunc is equivalent to converting `fn` to a [Func] except that the
+// returned [Want] is a [Describer], described by `desc`, so its expectation can
+// be reported by other [Want]s. It is intended for packages that extend this
+// one with their own diffs. A nil `fn` always results in a diff.
+func DescribedFunc(desc string, fn Func) Want {
+	if fn == nil {
+		return &described{
+			desc: desc,
+			diff: func(got error) string {
+				return DiffMessage(got, "%s but DescribedFunc() function is nil", desc)
+			},
+		}
+	}
+	return &described{desc: desc, diff: fn}
+}
+
+// Predicate returns a [Want] that passes the `got` error, even if nil, to `fn`
+// and, if it returns false, produces the canonical diff with `desc` as the
+// expectation. This allows for reuse of existing classification functions,
+// such as [os.IsTimeout]. A nil `fn` always results in a diff.
+func Predicate(desc string, fn func(error) bool) Want {
+	if fn == nil {
+		return &described{
+			desc: desc,
+			diff: func(got error) string {
+				return DiffMessage(got, "%s but Predicate() function is nil", desc)
+			},
+		}
+	}
+	return predicate(desc, fn)
+}
+
+// A Describer is a [Want] that can describe its own expectation, allowing it to
+// be reported by other [Want]s, such as [Not], without having to run the
+// comparison. All [Want]s returned by this package are Describers.
+type Describer interface {
+	Want
+	// Describe returns a description of the expected error, of the form that
+	// follows "want" in a diff; e.g. "error that Is() EOF".
+	Describe() string
+}
+
+// Describe returns a description of the expectation of `w`. A nil [Want] is
+// described as "nil", consistent with [Diff], and a [Want] that isn't a
+// [Describer] in terms of its concrete type.
+func Describe(w Want) string {
+	if isNil(w) {
+		return "nil"
+	}
+	if d, ok := w.(Describer); ok {
+		return d.Describe()
+	}
+	return fmt.Sprintf("expectation of type %T", w)
+}
+
+// described is a [Describer] with an arbitrary diffing function. It is also a
+// [Detailer], returning nil details if the respective function is nil. The
+// optional `match` function MUST return true if and only if `diff` returns an
+// empty string, and allows [Matches] to avoid constructing diffs.
+type described struct {
+	desc    string
+	diff    func(got error) string
+	match   func(got error) bool
+	details func(got error) map[string]any
+}
+
+func (d *described) ErrDiff(got error) string {
+	return d.diff(got)
+}
+
+func (d *described) Describe() string {
+	return d.desc
+}
+
+func (d *described) Details(got error) map[string]any {
+	if d.details == nil {
+		return nil
+	}
+	return d.details(got)
+}
+
+// predicate returns a [Want] that reports `desc` as its expectation whenever
+// `match` returns false.
+func predicate(desc string, match func(got error) bool) *described {
+	return &described{
+		desc:  desc,
+		match: match,
+		diff: func(got error) string {
+			if match(got) {
+				return ""
+			}
+			return DiffMessage(got, "%s", desc)
+		},
+	}
+}
+
 // Is checks that the `got` error [errors.Is] `target`.
 func Is(target error) Want {
-	return Func(func(got error) string {
-		if errors.Is(got, target) {
-			return ""
-		}
-		return DiffMessage(got, "error that Is() %v", target)
-	})
+	return predicate(
+		fmt.Sprintf("error that Is() %v", target),
+		func(got error) bool { return errors.Is(got, target) },
+	)
+}
+
+// IsAll checks that the `got` error [errors.Is] every one of the `targets`,
+// reporting those that are missing. As with [All], it rejects an empty list of
+// targets, and a nil target is always reported as an error in the [Want]
+// itself, because [errors.Is] would only match it against a nil error.
+func IsAll(targets ...error) Want {
+	desc := fmt.Sprintf("error that Is() all of %v", targets)
+	return &described{
+		desc: desc,
+		match: func(got error) bool {
+			for _, t := range targets {
+				if t == nil || !errors.Is(got, t) {
+					return false
+				}
+			}
+			return len(targets) > 0
+		},
+		diff: func(got error) string {
+			if len(targets) == 0 {
+				return DiffMessage(got, "IsAll() of at least one target")
+			}
+			var missing []error
+			for i, t := range targets {
+				if t == nil {
+					return DiffMessage(got, "IsAll() of non-nil targets; got nil at index %d", i)
+				}
+				if !errors.Is(got, t) {
+					missing = append(missing, t)
+				}
+			}
+			if len(missing) == 0 {
+				return ""
+			}
+			return DiffMessage(got, "%s; missing %v", desc, missing)
+		},
+	}
 }
 
 // As creates a new `T` and checks that the `got` error can be unwrapped via
@@ -62,36 +295,155 @@ func Is(target error) Want {
 // also returning an empty string. On mismatch there is no need to prepend the
 // `expected` description with the `got` message. See the [Diff] example.
 func As[T error](match func(got T) (expected string)) Want {
-	return Func(func(got error) string {
-		var target T
-		if !errors.As(got, &target) {
-			return DiffMessage(got, "error tree containing type %T", target)
+	desc := fmt.Sprintf("error tree containing type %v", reflect.TypeFor[T]())
+	return &described{
+		desc:    desc,
+		details: treeTypesDetails,
+		match: func(got error) bool {
+			target, ok := as[T](got)
+			return ok && match(target) == ""
+		},
+		diff: func(got error) string {
+			target, ok := as[T](got)
+			if !ok {
+				return typeDiff(got, desc)
+			}
+			if d := match(target); d != "" {
+				return DiffMessage(got, "%s", d)
+			}
+			return ""
+		},
+	}
+}
+
+// Implements checks that a node in the `got` error's tree implements the
+// interface `I`, for example `interface{ Timeout() bool }`, searching in the
+// same manner as [errors.As]. The first such node is passed to `check`, which
+// follows the same semantics as the `match()` function passed to [As]. A nil
+// `check` accepts all implementations. A non-interface `I` results in a diff.
+func Implements[I any](check func(I) (expected string)) Want {
+	typ := reflect.TypeFor[I]()
+	desc := fmt.Sprintf("error tree containing implementation of %v", typ)
+
+	return &described{
+		desc:    desc,
+		details: treeTypesDetails,
+		diff: func(got error) string {
+			if typ.Kind() != reflect.Interface {
+				return DiffMessage(got, "Implements() of interface type; got %v", typ)
+			}
+			impl, ok := as[I](got)
+			if !ok {
+				return typeDiff(got, desc)
+			}
+			if check == nil {
+				return ""
+			}
+			if d := check(impl); d != "" {
+				return DiffMessage(got, "%s; found %T but check failed: %s", desc, impl, d)
+			}
+			return ""
+		},
+	}
+}
+
+// IsType checks that the `got` error can be unwrapped via [errors.As] to a `T`.
+// It is equivalent to an [As] matcher that accepts all values of type `T`. On
+// mismatch, the diff lists the concrete types in the error tree.
+func IsType[T error]() Want {
+	desc := fmt.Sprintf("error tree containing type %v", reflect.TypeFor[T]())
+	return &described{
+		desc:    desc,
+		details: treeTypesDetails,
+		match: func(got error) bool {
+			_, ok := as[T](got)
+			return ok
+		},
+		diff: func(got error) string {
+			if _, ok := as[T](got); ok {
+				return ""
+			}
+			return typeDiff(got, desc)
+		},
+	}
+}
+
+// typeDiff returns a diff, for a failed type-based expectation, that lists the
+// concrete types in the `got` error's tree.
+func typeDiff(got error, desc string) string {
+	if got == nil {
+		return DiffMessage(got, "%s", desc)
+	}
+	return DiffMessage(got, "%s; found types %s", desc, treeTypesList(got))
+}
+
+// as is equivalent to [errors.As] but returns the target instead of populating
+// a pointer. This avoids the allocation of a new `T` for every call, which is
+// otherwise necessary as the pointer escapes; allocation only occurs when
+// consulting an `As(any) bool` method.
+func as[T any](err error) (T, bool) {
+	if err == nil {
+		var zero T
+		return zero, false
+	}
+	if t, ok := err.(T); ok {
+		return t, true
+	}
+	if x, ok := err.(interface{ As(any) bool }); ok {
+		var t T
+		if x.As(&t) {
+			return t, true
 		}
-		if d := match(target); d != "" {
-			return DiffMessage(got, "%s", d)
+	}
+
+	switch err := err.(type) {
+	case interface{ Unwrap() error }:
+		return as[T](err.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, e := range err.Unwrap() {
+			if t, ok := as[T](e); ok {
+				return t, true
+			}
 		}
-		return ""
-	})
+	}
+	var zero T
+	return zero, false
 }
 
 // Equals checks that `got == want`. [Is] SHOULD be used instead.
 func Equals(want error) Want {
-	return Func(func(got error) string {
-		if got == want {
-			return ""
-		}
-		return DiffMessage(got, "== %v", want)
-	})
+	return predicate(
+		fmt.Sprintf("== %v", want),
+		func(got error) bool { return got == want },
+	)
 }
 
 // Contains checks that the `got` error's string contains the substring. Note
 // that the empty string is *not* the same as a nil error, for which a nil
 // [Want] MUST be used.
 func Contains(substr string) Want {
-	return Func(func(got error) string {
-		if got != nil && strings.Contains(got.Error(), substr) {
-			return ""
-		}
-		return DiffMessage(got, "containing substring %q", substr)
-	})
+	desc := fmt.Sprintf("containing substring %q", substr)
+	return &described{
+		desc: desc,
+		match: func(got error) bool {
+			if got == nil {
+				return false
+			}
+			msg, ok := message(got)
+			return ok && strings.Contains(msg, substr)
+		},
+		diff: func(got error) string {
+			if got == nil {
+				return DiffMessage(got, "%s", desc)
+			}
+			msg, ok := message(got)
+			if !ok {
+				return DiffMessage(got, "%s", desc)
+			}
+			if strings.Contains(msg, substr) {
+				return ""
+			}
+			return diffMessageAt(got, nearestPartialMatch(msg, substr), "%s", desc)
+		},
+	}
 }
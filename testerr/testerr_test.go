@@ -1,8 +1,15 @@
 package testerr_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/arr4n/shed/testerr"
@@ -149,5 +156,373 @@ func ExampleDiff() {
 	// --- As() with diff from matcher function ---
 	// got error val 43 is not good; want 42 (of course)
 	// --- As() with incorrect type ---
-	// got error uh oh; want error tree containing type testerr_test.myError
+	// got error uh oh; want error tree containing type testerr_test.myError; found types [*errors.errorString]
+}
+
+func ExampleIsAll() {
+	var (
+		errRetryable   = errors.New("retryable")
+		errRateLimited = errors.New("rate limited")
+		errQuota       = errors.New("quota")
+	)
+	err := errors.Join(
+		fmt.Errorf("attempt 1: %w", errRetryable),
+		fmt.Errorf("attempt 2: %w", errRateLimited),
+	)
+
+	for _, want := range []testerr.Want{
+		testerr.IsAll(errRetryable, errRateLimited),
+		testerr.IsAll(errRetryable, errRateLimited, errQuota),
+		testerr.IsAll(errRetryable, nil),
+		testerr.IsAll(),
+	} {
+		if diff := testerr.Diff(err, want); diff != "" {
+			fmt.Println(strings.ReplaceAll(diff, "\n", `\n`))
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// got error attempt 1: retryable\nattempt 2: rate limited; want error that Is() all of [retryable rate limited quota]; missing [quota]
+	// got error attempt 1: retryable\nattempt 2: rate limited; want IsAll() of non-nil targets; got nil at index 1
+	// got error attempt 1: retryable\nattempt 2: rate limited; want IsAll() of at least one target
+}
+
+func ExampleIsType() {
+	err := &fs.PathError{Op: "open", Path: "config.json", Err: fs.ErrNotExist}
+	wrapped := fmt.Errorf("loading: %w", err)
+
+	for _, tt := range []struct {
+		err  error
+		want testerr.Want
+	}{
+		{wrapped, testerr.IsType[*fs.PathError]()},
+		{wrapped, testerr.IsType[*net.OpError]()},
+		{errors.Join(wrapped, io.EOF), testerr.IsType[*net.OpError]()},
+		{nil, testerr.IsType[*fs.PathError]()},
+	} {
+		if diff := testerr.Diff(tt.err, tt.want); diff != "" {
+			fmt.Println(strings.ReplaceAll(diff, "\n", `\n`))
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// got error loading: open config.json: file does not exist; want error tree containing type *net.OpError; found types [*fmt.wrapError, *fs.PathError, *errors.errorString]
+	// got error loading: open config.json: file does not exist\nEOF; want error tree containing type *net.OpError; found types [*errors.joinError, *fmt.wrapError, *fs.PathError, *errors.errorString]
+	// got error <nil>; want error tree containing type *fs.PathError
+}
+
+// timeoutError is an error that implements a behavioral interface.
+type timeoutError struct {
+	timeout bool
+}
+
+func (e timeoutError) Error() string { return fmt.Sprintf("timeout=%t", e.timeout) }
+func (e timeoutError) Timeout() bool { return e.timeout }
+
+func ExampleImplements() {
+	type timeout interface{ Timeout() bool }
+	isTimeout := testerr.Implements(func(e timeout) string {
+		if !e.Timeout() {
+			return "Timeout() == true"
+		}
+		return ""
+	})
+
+	for _, tt := range []struct {
+		err  error
+		want testerr.Want
+	}{
+		// The interface is satisfied by a wrapped error, not the outermost.
+		{fmt.Errorf("dial: %w", timeoutError{true}), isTimeout},
+		{fmt.Errorf("dial: %w", timeoutError{false}), isTimeout},
+		{fmt.Errorf("dial: %w", timeoutError{false}), testerr.Implements[timeout](nil)},
+		{fmt.Errorf("dial: %w", io.EOF), isTimeout},
+		{io.EOF, testerr.Implements[myError](nil)},
+	} {
+		if diff := testerr.Diff(tt.err, tt.want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// got error dial: timeout=false; want error tree containing implementation of testerr_test.timeout; found testerr_test.timeoutError but check failed: Timeout() == true
+	// <empty>
+	// got error dial: EOF; want error tree containing implementation of testerr_test.timeout; found types [*fmt.wrapError, *errors.errorString]
+	// got error EOF; want Implements() of interface type; got testerr_test.myError
+}
+
+func ExamplePredicate() {
+	isNilOrEOF := testerr.Predicate("nil or EOF", func(err error) bool {
+		return err == nil || errors.Is(err, io.EOF)
+	})
+	timeout := testerr.Predicate("timeout", os.IsTimeout)
+
+	for _, tt := range []struct {
+		err  error
+		want testerr.Want
+	}{
+		{nil, isNilOrEOF},
+		{fmt.Errorf("read: %w", io.EOF), isNilOrEOF},
+		{io.ErrUnexpectedEOF, isNilOrEOF},
+		{os.ErrDeadlineExceeded, timeout},
+		{io.EOF, timeout},
+		{io.EOF, testerr.Predicate("anything", nil)},
+	} {
+		if diff := testerr.Diff(tt.err, tt.want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// <empty>
+	// got error unexpected EOF; want nil or EOF
+	// <empty>
+	// got error EOF; want timeout
+	// got error EOF; want anything but Predicate() function is nil
+}
+
+func ExampleDescribedFunc() {
+	const desc = "error with an even-length message"
+	evenLength := testerr.DescribedFunc(desc, func(got error) string {
+		if got == nil {
+			return testerr.DiffMessage(got, "%s", desc)
+		}
+		if n := len(got.Error()); n%2 != 0 {
+			return testerr.DiffMessage(got, "%s; got %d bytes", desc, n)
+		}
+		return ""
+	})
+
+	for _, tt := range []struct {
+		err  error
+		want testerr.Want
+	}{
+		{errors.New("even"), evenLength},
+		{io.EOF, evenLength},
+		{io.EOF, testerr.Not(evenLength)},
+		{errors.New("even"), testerr.Not(evenLength)},
+		{io.EOF, testerr.DescribedFunc("anything", nil)},
+	} {
+		if diff := testerr.Diff(tt.err, tt.want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// <empty>
+	// got error EOF; want error with an even-length message; got 3 bytes
+	// <empty>
+	// got error even; want NOT (error with an even-length message)
+	// got error EOF; want anything but DescribedFunc() function is nil
+}
+
+// customWant is a [testerr.Want] with a pointer receiver that dereferences
+// itself, so would panic if a typed-nil value were used.
+type customWant struct {
+	substr string
+}
+
+func (w *customWant) ErrDiff(got error) string {
+	return testerr.Diff(got, testerr.Contains(w.substr))
+}
+
+// customWantFor is an example of a helper that returns a concrete type.
+func customWantFor(substr string) *customWant {
+	if substr == "" {
+		return nil
+	}
+	return &customWant{substr}
+}
+
+func TestNilWants(t *testing.T) {
+	var nilFunc testerr.Func
+
+	tests := []struct {
+		name string
+		want testerr.Want
+	}{
+		{"untyped nil", nil},
+		{"Nil()", testerr.Nil()},
+		{"typed-nil pointer", customWantFor("")},
+		{"nil Func", nilFunc},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := testerr.Diff(nil, tt.want); diff != "" {
+				t.Errorf("Diff(nil, [%s]) %s", tt.name, diff)
+			}
+			const want = "got error EOF; want nil"
+			if got := testerr.Diff(io.EOF, tt.want); got != want {
+				t.Errorf("Diff(io.EOF, [%s]) got %q; want %q", tt.name, got, want)
+			}
+			if got, want := testerr.Describe(tt.want), "nil"; got != want {
+				t.Errorf("Describe([%s]) got %q; want %q", tt.name, got, want)
+			}
+			for _, w := range []testerr.Want{testerr.All(tt.want), testerr.Any(tt.want), testerr.Not(testerr.Not(tt.want))} {
+				if diff := testerr.Diff(nil, w); diff != "" {
+					t.Errorf("Diff(nil, %s) %s", testerr.Describe(w), diff)
+				}
+			}
+		})
+	}
+
+	// Non-nil values of the same types MUST still be used.
+	if diff := testerr.Diff(io.EOF, customWantFor("EOF")); diff != "" {
+		t.Errorf("Diff(io.EOF, &customWant{EOF}) %s", diff)
+	}
+}
+
+// ptrError is an error type with a pointer receiver that, like most such
+// errors, panics if its Error() method is called on a nil pointer.
+type ptrError struct {
+	msg string
+}
+
+func (e *ptrError) Error() string { return e.msg }
+
+// newPtrError is an example of the classic bug of returning a typed nil.
+func newPtrError(fail bool) error {
+	var err *ptrError
+	if fail {
+		err = &ptrError{"failed"}
+	}
+	return err
+}
+
+func ExampleNotTypedNil() {
+	for _, tt := range []struct {
+		err  error
+		want testerr.Want
+	}{
+		{newPtrError(false), nil},
+		{newPtrError(false), testerr.NotTypedNil()},
+		{newPtrError(false), testerr.Is(newPtrError(false))},
+		{newPtrError(true), testerr.NotTypedNil()},
+		{nil, testerr.NotTypedNil()},
+	} {
+		if diff := testerr.Diff(tt.err, tt.want); diff != "" {
+			fmt.Println(diff)
+		} else {
+			fmt.Println("<empty>")
+		}
+	}
+
+	// Output:
+	// got non-nil error of type *testerr_test.ptrError holding nil pointer; want nil
+	// got non-nil error of type *testerr_test.ptrError holding nil pointer; want error that isn't a typed nil
+	// <empty>
+	// <empty>
+	// <empty>
+}
+
+func TestDescribe(t *testing.T) {
+	errUhOh := errors.New("uh oh")
+
+	tests := []struct {
+		want testerr.Want
+		desc string
+	}{
+		{nil, "nil"},
+		{testerr.Is(errUhOh), "error that Is() uh oh"},
+		{testerr.As(func(myError) string { return "" }), "error tree containing type testerr_test.myError"},
+		{testerr.As(func(error) string { return "" }), "error tree containing type error"},
+		{testerr.IsAll(io.EOF, io.ErrUnexpectedEOF), "error that Is() all of [EOF unexpected EOF]"},
+		{testerr.IsType[*fs.PathError](), "error tree containing type *fs.PathError"},
+		{testerr.Equals(io.EOF), "== EOF"},
+		{testerr.Contains("foo"), `containing substring "foo"`},
+		{testerr.MatchesRegexp(`^a\d+`), `message matching regexp "^a\\d+"`},
+		{testerr.Predicate("custom", func(error) bool { return true }), "custom"},
+		{testerr.NotTypedNil(), "error that isn't a typed nil"},
+		{testerr.Func(func(error) string { return "" }), "expectation of type testerr.Func"},
+		{
+			testerr.All(testerr.Is(io.EOF), testerr.Contains("bar"), nil),
+			`all of [error that Is() EOF; containing substring "bar"; nil]`,
+		},
+		{testerr.Any(testerr.Is(io.EOF), nil), "any of [error that Is() EOF; nil]"},
+		{testerr.All(), "all of []"},
+		{testerr.Not(testerr.Is(io.EOF)), "NOT (error that Is() EOF)"},
+		{testerr.Not(nil), "non-nil error"},
+		{testerr.Named("custom", testerr.Is(io.EOF)), "custom"},
+		{testerr.CountMatching(testerr.Is(io.EOF), 2), "2 error(s) in tree matching (error that Is() EOF)"},
+	}
+
+	for _, tt := range tests {
+		if got := testerr.Describe(tt.want); got != tt.desc {
+			t.Errorf("Describe(%T) got %q; want %q", tt.want, got, tt.desc)
+		}
+		if tt.want == nil {
+			continue
+		}
+		if _, ok := tt.want.(testerr.Describer); !ok && tt.desc != "expectation of type testerr.Func" {
+			t.Errorf("%T does not implement testerr.Describer", tt.want)
+		}
+	}
+}
+
+func ExampleMatchFunc() {
+	errUnavailable := errors.New("unavailable")
+	retryable := testerr.MatchFunc(testerr.Any(
+		testerr.Is(errUnavailable),
+		testerr.Is(context.DeadlineExceeded),
+	))
+
+	// retry is an example of a non-test helper that accepts a predicate.
+	retry := func(fn func() error, shouldRetry func(error) bool) (attempts int, _ error) {
+		for {
+			attempts++
+			if err := fn(); err == nil || !shouldRetry(err) || attempts == 5 {
+				return attempts, err
+			}
+		}
+	}
+
+	var calls int
+	attempts, err := retry(func() error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("dial: %w", errUnavailable)
+		}
+		return io.EOF
+	}, retryable)
+
+	fmt.Println(attempts, err)
+	fmt.Println(testerr.Matches(nil, nil), testerr.Matches(io.EOF, nil))
+
+	// Output:
+	// 3 EOF
+	// true false
+}
+
+// FuzzMatchFunc demonstrates the rejection of unexpected errors in a fuzz
+// target.
+func FuzzMatchFunc(f *testing.F) {
+	expected := testerr.MatchFunc(testerr.Any(
+		nil,
+		testerr.Is(strconv.ErrSyntax),
+		testerr.Is(strconv.ErrRange),
+	))
+
+	for _, s := range []string{"0", "-42", "x", "99999999999999999999"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		if _, err := strconv.Atoi(s); !expected(err) {
+			t.Errorf("strconv.Atoi(%q) unexpected error %v", s, err)
+		}
+	})
 }
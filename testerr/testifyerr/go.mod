@@ -0,0 +1,15 @@
+module github.com/arr4n/shed/testerr/testifyerr
+
+go 1.24.8
+
+replace github.com/arr4n/shed => ../..
+
+require (
+	github.com/arr4n/shed v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.12.1
+)
+
+require (
+	github.com/google/go-cmp v0.7.0 // indirect
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+)
@@ -0,0 +1,26 @@
+// Package testifyerr adapts [testerr.Want] values for use with
+// [github.com/stretchr/testify]. It is a separate module so that the testerr
+// package doesn't depend on testify.
+package testifyerr
+
+import (
+	"github.com/arr4n/shed/testerr"
+	"github.com/stretchr/testify/assert"
+)
+
+// ErrorAssertionFunc returns an [assert.ErrorAssertionFunc] that fails, via
+// [assert.Fail], if [testerr.Diff] of the error and `want` is non-empty. The
+// diff is reported as the failure message, with any `msgAndArgs` appended in
+// the usual testify manner. As with [testerr.Diff], a nil `want` expects a nil
+// error, equivalent to [assert.NoError].
+func ErrorAssertionFunc(want testerr.Want) assert.ErrorAssertionFunc {
+	return func(t assert.TestingT, err error, msgAndArgs ...any) bool {
+		if h, ok := t.(interface{ Helper() }); ok {
+			h.Helper()
+		}
+		if diff := testerr.Diff(err, want); diff != "" {
+			return assert.Fail(t, diff, msgAndArgs...)
+		}
+		return true
+	}
+}
@@ -0,0 +1,109 @@
+package testifyerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+	"github.com/arr4n/shed/testerr/testifyerr"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is an [assert.TestingT] that records failures instead of reporting
+// them.
+type recorder struct {
+	helper bool
+	errors []string
+}
+
+func (r *recorder) Helper() { r.helper = true }
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestErrorAssertionFunc(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		want      testerr.Want
+		wantOK    bool
+		wantInMsg []string
+	}{
+		{
+			name:   "match",
+			err:    fmt.Errorf("read: %w", io.EOF),
+			want:   testerr.Is(io.EOF),
+			wantOK: true,
+		},
+		{
+			name: "mismatch",
+			err:  errors.New("uh oh"),
+			want: testerr.Is(io.EOF),
+			wantInMsg: []string{
+				"got error uh oh; want error that Is() EOF",
+				"Read(42)",
+			},
+		},
+		{
+			name:   "nil Want with nil error",
+			err:    nil,
+			want:   nil,
+			wantOK: true,
+		},
+		{
+			name:      "nil Want with non-nil error",
+			err:       io.EOF,
+			want:      nil,
+			wantInMsg: []string{"got error EOF; want nil", "Read(42)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := new(recorder)
+			fn := testifyerr.ErrorAssertionFunc(tt.want)
+
+			if got := fn(rec, tt.err, "Read(%d)", 42); got != tt.wantOK {
+				t.Errorf("ErrorAssertionFunc(%s)(t, %v, …) got %t; want %t", testerr.Describe(tt.want), tt.err, got, tt.wantOK)
+			}
+			if !rec.helper {
+				t.Error("ErrorAssertionFunc() didn't call t.Helper()")
+			}
+
+			if tt.wantOK {
+				if len(rec.errors) != 0 {
+					t.Errorf("ErrorAssertionFunc() on match reported %q; want no failures", rec.errors)
+				}
+				return
+			}
+			if len(rec.errors) != 1 {
+				t.Fatalf("ErrorAssertionFunc() on mismatch reported %d failures; want 1", len(rec.errors))
+			}
+			for _, want := range tt.wantInMsg {
+				if !strings.Contains(rec.errors[0], want) {
+					t.Errorf("ErrorAssertionFunc() on mismatch reported %q; want containing %q", rec.errors[0], want)
+				}
+			}
+		})
+	}
+}
+
+// TestTable demonstrates use alongside testify's own [assert.ErrorAssertionFunc]
+// values.
+func TestTable(t *testing.T) {
+	for _, tt := range []struct {
+		err       error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{nil, assert.NoError},
+		{io.EOF, assert.Error},
+		{nil, testifyerr.ErrorAssertionFunc(nil)},
+		{fmt.Errorf("read: %w", io.EOF), testifyerr.ErrorAssertionFunc(testerr.Is(io.EOF))},
+	} {
+		tt.assertion(t, tt.err, "err = %v", tt.err)
+	}
+}
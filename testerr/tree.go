@@ -0,0 +1,322 @@
+package testerr
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Walk calls `fn` for every node in the error tree rooted at `err`, in
+// pre-order and left-to-right with respect to `Unwrap() []error`, until `fn`
+// returns false. Both `Unwrap() error` and `Unwrap() []error` are followed, and
+// any nil children are skipped, so `fn` is never called with a nil error. Typed
+// nils, which can't be relied upon to unwrap, are also skipped. A node
+// that is equal to one of its own ancestors is not visited again, which
+// guarantees termination of cyclic trees.
+func Walk(err error, fn func(error) bool) {
+	walkPath(err, nil, fn)
+}
+
+// walk is equivalent to [Walk] without the ability to stop early.
+func walk(err error, fn func(error)) {
+	Walk(err, func(node error) bool {
+		fn(node)
+		return true
+	})
+}
+
+// walkPath returns false iff `fn` did, stopping the walk.
+func walkPath(err error, ancestors []error, fn func(error) bool) bool {
+	if _, ok := typedNil(err); ok || err == nil || isAncestor(err, ancestors) {
+		return true
+	}
+	if !fn(err) {
+		return false
+	}
+
+	ancestors = append(ancestors, err)
+	switch err := err.(type) {
+	case interface{ Unwrap() error }:
+		return walkPath(err.Unwrap(), ancestors, fn)
+	case interface{ Unwrap() []error }:
+		for _, e := range err.Unwrap() {
+			if !walkPath(e, ancestors, fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isAncestor reports whether `err` is equal to any of the `ancestors`. Only
+// errors of pointer-shaped kinds are compared, which suffices to detect cycles
+// as they require indirection. Comparing other types, even if comparable, can
+// panic; e.g. structs with interface fields holding non-comparable values.
+func isAncestor(err error, ancestors []error) bool {
+	switch reflect.TypeOf(err).Kind() {
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+	default:
+		return false
+	}
+	for _, a := range ancestors {
+		if a == err {
+			return true
+		}
+	}
+	return false
+}
+
+// treeTypes returns the distinct concrete types of all nodes in the error tree,
+// in the order of first appearance in [walk].
+func treeTypes(err error) []string {
+	var types []string
+	walk(err, func(node error) {
+		t := fmt.Sprintf("%T", node)
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	})
+	return types
+}
+
+// treeTypesList returns the [treeTypes] formatted as a bracketed list.
+func treeTypesList(err error) string {
+	return fmt.Sprintf("[%s]", strings.Join(treeTypes(err), ", "))
+}
+
+// treeTypesDetails returns [Detailer] details that include the [treeTypes].
+func treeTypesDetails(got error) map[string]any {
+	return map[string]any{"tree_types": treeTypes(got)}
+}
+
+// CountMatching checks that exactly `n` nodes in the `got` error's tree, as
+// walked via both `Unwrap() error` and `Unwrap() []error`, result in an empty
+// diff from `w`. Each node is checked independently so, for example, an [Is]
+// [Want] will match both a sentinel and every error that wraps it.
+//
+// A nil `w` is treated in the same manner as by [Diff], but as a nil error is
+// never part of a tree, such a [Want] can only be satisfied with `n == 0`.
+func CountMatching(w Want, n int) Want {
+	desc := fmt.Sprintf("%d error(s) in tree matching (%s)", n, Describe(w))
+	return &described{
+		desc: desc,
+		diff: func(got error) string {
+			var count int
+			walk(got, func(node error) {
+				if Matches(node, w) {
+					count++
+				}
+			})
+			if count == n {
+				return ""
+			}
+
+			var matched, unmatched []string
+			walk(got, func(node error) {
+				if Matches(node, w) {
+					matched = append(matched, quoteMessage(node))
+				} else {
+					unmatched = append(unmatched, quoteMessage(node))
+				}
+			})
+			return DiffMessage(
+				got, "%s; found %d [matched: %s; unmatched: %s]",
+				desc, count, strings.Join(matched, ", "), strings.Join(unmatched, ", "),
+			)
+		},
+	}
+}
+
+// AnywhereInTree checks that at least one node in the `got` error's tree, as
+// visited by [Walk], results in an empty diff from `w`. This generalizes [Is]
+// and [As] to any [Want]; e.g. a [MatchesRegexp] that [Contains] can't express
+// against the message of an entire joined error. On mismatch, the diff
+// summarizes the tree by its number of nodes and the messages of its leaves.
+//
+// As with [CountMatching], a nil `w` can never be satisfied because a nil error
+// is never part of a tree.
+func AnywhereInTree(w Want) Want {
+	desc := fmt.Sprintf("error tree with any node matching (%s)", Describe(w))
+	anywhere := func(got error) bool {
+		var found bool
+		Walk(got, func(node error) bool {
+			found = Matches(node, w)
+			return !found
+		})
+		return found
+	}
+
+	return &described{
+		desc:  desc,
+		match: anywhere,
+		diff: func(got error) string {
+			if anywhere(got) {
+				return ""
+			}
+			if got == nil {
+				return DiffMessage(got, "%s", desc)
+			}
+
+			var n int
+			var leaves []string
+			walk(got, func(node error) {
+				n++
+				if isLeaf(node) {
+					leaves = append(leaves, quoteMessage(node))
+				}
+			})
+			if len(leaves) > maxListedComponents {
+				leaves = append(leaves[:maxListedComponents], fmt.Sprintf("…and %d more", len(leaves)-maxListedComponents))
+			}
+			return DiffMessage(
+				got, "%s; none of %d node(s) matched [leaves: %s]",
+				desc, n, strings.Join(leaves, ", "),
+			)
+		},
+	}
+}
+
+// isLeaf reports whether `err` has no non-nil children to be visited by
+// [Walk]. A node whose only children close a cycle is therefore not a leaf.
+func isLeaf(err error) bool {
+	switch err := err.(type) {
+	case interface{ Unwrap() error }:
+		return err.Unwrap() == nil
+	case interface{ Unwrap() []error }:
+		for _, e := range err.Unwrap() {
+			if e != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Count checks that exactly `n` nodes in the `got` error's tree, walked as by
+// [CountMatching], are themselves `target`. A node is counted if it is equal to
+// `target` or if its own `Is(error) bool` method returns true, but not merely
+// because it wraps `target`, so, unlike with [Is], each occurrence is counted
+// once regardless of the number of wrapping layers. The same error value
+// appearing at multiple positions, such as [errors.Join] of `target` with
+// itself, is counted at each. On mismatch, the diff reports every position at
+// which `target` was found, from the root `$`, with `.Unwrap()` denoting a
+// layer of `Unwrap() error` and `[i]` the i-th error of `Unwrap() []error`.
+func Count(target error, n int) Want {
+	desc := fmt.Sprintf("%d occurrence(s) of %v in tree", n, target)
+	return &described{
+		desc:  desc,
+		match: func(got error) bool { return countAt(got, target) == n },
+		diff: func(got error) string {
+			if countAt(got, target) == n {
+				return ""
+			}
+			var found []string
+			walkPaths(got, "$", nil, func(node error, path string) {
+				if isAt(node, target) {
+					found = append(found, path)
+				}
+			})
+			if len(found) == 0 {
+				return DiffMessage(got, "%s; found 0", desc)
+			}
+			return DiffMessage(got, "%s; found %d at %s", desc, len(found), strings.Join(found, ", "))
+		},
+	}
+}
+
+// countAt returns the number of nodes in the `got` error's tree that are
+// `target`, as defined by [Count].
+func countAt(got, target error) int {
+	var count int
+	walk(got, func(node error) {
+		if isAt(node, target) {
+			count++
+		}
+	})
+	return count
+}
+
+// isAt reports whether the non-nil `node` is `target`, as defined by [Count].
+// As nil errors are never part of a tree, a nil `target` is never found.
+func isAt(node, target error) bool {
+	if target == nil {
+		return false
+	}
+	if reflect.TypeOf(node).Comparable() && node == target {
+		return true
+	}
+	x, ok := node.(interface{ Is(error) bool })
+	return ok && x.Is(target)
+}
+
+// walkPaths is equivalent to [walk] except that `fn` also receives the path to
+// each node, as described by [Count].
+func walkPaths(err error, path string, ancestors []error, fn func(node error, path string)) {
+	if _, ok := typedNil(err); ok || err == nil || isAncestor(err, ancestors) {
+		return
+	}
+	fn(err, path)
+
+	ancestors = append(ancestors, err)
+	switch err := err.(type) {
+	case interface{ Unwrap() error }:
+		walkPaths(err.Unwrap(), path+".Unwrap()", ancestors, fn)
+	case interface{ Unwrap() []error }:
+		for i, e := range err.Unwrap() {
+			walkPaths(e, fmt.Sprintf("%s[%d]", path, i), ancestors, fn)
+		}
+	}
+}
+
+// maxRenderDepth is the maximum depth of a tree rendered by [DiffVerbose].
+const maxRenderDepth = 32
+
+// DiffVerbose is equivalent to [Diff] except that, on mismatch, the diff is
+// followed by a rendering of the `got` error's tree, with one line per node,
+// indented to reflect `Unwrap() error` chains and `Unwrap() []error`
+// branches. Nodes that are equal to one of their ancestors are marked as
+// cycles and not descended into, and trees are truncated beyond a fixed depth.
+func DiffVerbose(got error, want Want) string {
+	diff := Diff(got, want)
+	if diff == "" || got == nil {
+		return diff
+	}
+	var b strings.Builder
+	b.WriteString(diff)
+	b.WriteString("\nerror tree:")
+	renderTree(&b, got, nil)
+	return b.String()
+}
+
+func renderTree(b *strings.Builder, err error, ancestors []error) {
+	indent := strings.Repeat("  ", len(ancestors)+1)
+	if len(ancestors) == maxRenderDepth {
+		fmt.Fprintf(b, "\n%s…truncated", indent)
+		return
+	}
+	if isAncestor(err, ancestors) {
+		fmt.Fprintf(b, "\n%s%T (cycle)", indent, err)
+		return
+	}
+
+	if _, ok := typedNil(err); ok {
+		fmt.Fprintf(b, "\n%s%T (typed nil)", indent, err)
+		return
+	}
+	fmt.Fprintf(b, "\n%s%T %s", indent, err, quoteMessage(err))
+
+	ancestors = append(ancestors, err)
+	switch err := err.(type) {
+	case interface{ Unwrap() error }:
+		if u := err.Unwrap(); u != nil {
+			renderTree(b, u, ancestors)
+		}
+	case interface{ Unwrap() []error }:
+		for _, e := range err.Unwrap() {
+			if e != nil {
+				renderTree(b, e, ancestors)
+			}
+		}
+	}
+}
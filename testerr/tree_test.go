@@ -0,0 +1,424 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/arr4n/shed/testerr"
+)
+
+// cyclicError unwraps to itself, which would cause a naive tree walk to never
+// terminate.
+type cyclicError struct{}
+
+func (e *cyclicError) Error() string { return "cyclic" }
+func (e *cyclicError) Unwrap() error { return e }
+
+func ExampleCountMatching() {
+	errUhOh := errors.New("uh oh")
+	tree := fmt.Errorf(
+		"[%w] [%w] [%w]",
+		myError{1},
+		fmt.Errorf("wrapped(%w)", myError{2}),
+		errUhOh,
+	)
+
+	isMyError := testerr.As(func(myError) string { return "" })
+
+	for _, n := range []int{4, 3} {
+		// The wrapping errors themselves also result in a match for
+		// `isMyError` because of how [errors.As] works.
+		if diff := testerr.Diff(tree, testerr.CountMatching(isMyError, n)); diff != "" {
+			fmt.Println(n, diff)
+		} else {
+			fmt.Println(n, "<empty>")
+		}
+	}
+
+	// Output:
+	// 4 <empty>
+	// 3 got error [val 1 is not good] [wrapped(val 2 is not good)] [uh oh]; want 3 error(s) in tree matching (error tree containing type testerr_test.myError); found 4 [matched: "[val 1 is not good] [wrapped(val 2 is not good)] [uh oh]", "val 1 is not good", "wrapped(val 2 is not good)", "val 2 is not good"; unmatched: "uh oh"]
+}
+
+func TestCountMatching(t *testing.T) {
+	errUhOh := errors.New("uh oh")
+
+	tests := []struct {
+		name     string
+		err      error
+		want     testerr.Want
+		n        int
+		wantDiff bool
+	}{
+		{
+			name: "nil error has no nodes",
+			want: testerr.Is(errUhOh),
+			n:    0,
+		},
+		{
+			name: "nil Want never matches a node",
+			err:  errUhOh,
+			n:    0,
+		},
+		{
+			name:     "nil Want with non-zero count",
+			err:      errUhOh,
+			n:        1,
+			wantDiff: true,
+		},
+		{
+			name: "Is() matches wrapping errors",
+			err:  fmt.Errorf("a: %w", fmt.Errorf("b: %w", errUhOh)),
+			want: testerr.Is(errUhOh),
+			n:    3,
+		},
+		{
+			name: "multiple wrap targets",
+			err:  fmt.Errorf("%w + %w", errUhOh, errors.New("other")),
+			want: testerr.Contains("uh oh"),
+			n:    2, // root + errUhOh
+		},
+		{
+			name: "cycle terminates",
+			err:  fmt.Errorf("wrapped: %w", &cyclicError{}),
+			want: testerr.Contains("cyclic"),
+			n:    2,
+		},
+		{
+			name:     "count mismatch",
+			err:      errors.Join(errUhOh, errUhOh),
+			want:     testerr.Equals(errUhOh),
+			n:        1,
+			wantDiff: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := testerr.Diff(tt.err, testerr.CountMatching(tt.want, tt.n))
+			if got := diff != ""; got != tt.wantDiff {
+				t.Errorf("Diff(%v, CountMatching(…, %d)) got diff %q; want non-empty = %t", tt.err, tt.n, diff, tt.wantDiff)
+			}
+		})
+	}
+}
+
+// deepError unwraps to a new instance of itself, with one fewer layers, until
+// it reaches zero.
+type deepError struct{ layers int }
+
+func (e deepError) Error() string { return fmt.Sprintf("deep %d", e.layers) }
+func (e deepError) Unwrap() error {
+	if e.layers == 0 {
+		return nil
+	}
+	return deepError{e.layers - 1}
+}
+
+func ExampleDiffVerbose() {
+	err := fmt.Errorf("handler: %w", errors.Join(
+		fmt.Errorf("store: %w", io.EOF),
+		&cyclicError{},
+	))
+
+	// Note that [errors.Is] doesn't terminate on cyclic trees.
+	fmt.Println(testerr.DiffVerbose(err, testerr.Contains("sentinel")))
+	fmt.Println("---")
+	fmt.Printf("%q\n", testerr.DiffVerbose(err, testerr.Contains("EOF")))
+
+	// Output:
+	// got error handler: store: EOF
+	// cyclic; want containing substring "sentinel"
+	// error tree:
+	//   *fmt.wrapError "handler: store: EOF\ncyclic"
+	//     *errors.joinError "store: EOF\ncyclic"
+	//       *fmt.wrapError "store: EOF"
+	//         *errors.errorString "EOF"
+	//       *testerr_test.cyclicError "cyclic"
+	//         *testerr_test.cyclicError (cycle)
+	// ---
+	// ""
+}
+
+func TestDiffVerboseTruncation(t *testing.T) {
+	got := testerr.DiffVerbose(deepError{100}, testerr.Is(io.EOF))
+	if !strings.HasSuffix(got, "…truncated") {
+		t.Errorf("DiffVerbose(<very deep chain>, …) got %q; want suffix %q", got, "…truncated")
+	}
+	if n, want := strings.Count(got, "testerr_test.deepError"), 32; n != want {
+		t.Errorf("DiffVerbose(<very deep chain>, …) rendered %d nodes; want %d", n, want)
+	}
+}
+
+var errAttemptFailed = errors.New("attempt failed")
+
+func ExampleCount() {
+	err := fmt.Errorf("retries exhausted: %w", errors.Join(
+		fmt.Errorf("attempt 1: %w", errAttemptFailed),
+		fmt.Errorf("attempt 2: %w", errAttemptFailed),
+		errors.New("attempt 3: canceled"),
+	))
+
+	fmt.Println(testerr.Diff(err, testerr.Count(errAttemptFailed, 3)))
+
+	// Output:
+	// got error retries exhausted: attempt 1: attempt failed
+	// attempt 2: attempt failed
+	// attempt 3: canceled; want 3 occurrence(s) of attempt failed in tree; found 2 at $.Unwrap()[0].Unwrap(), $.Unwrap()[1].Unwrap()
+}
+
+// isEOF is an error that claims, via its Is() method, to be io.EOF.
+type isEOF struct{}
+
+func (isEOF) Error() string        { return "is EOF" }
+func (isEOF) Is(target error) bool { return target == io.EOF }
+
+func TestCount(t *testing.T) {
+	deep := fmt.Errorf("a: %w", fmt.Errorf("b: %w", fmt.Errorf("c: %w", io.EOF)))
+	cyclic := &cyclicError{}
+
+	tests := []struct {
+		name     string
+		err      error
+		target   error
+		n        int
+		wantDiff string // following "want"; empty if no diff is expected
+	}{
+		{
+			name:   "nil error",
+			target: io.EOF,
+			n:      0,
+		},
+		{
+			name:     "nil error with non-zero count",
+			target:   io.EOF,
+			n:        1,
+			wantDiff: "1 occurrence(s) of EOF in tree; found 0",
+		},
+		{
+			name:   "wrapping layers not counted",
+			err:    deep,
+			target: io.EOF,
+			n:      1,
+		},
+		{
+			name:   "target at different depths",
+			err:    errors.Join(io.EOF, deep, fmt.Errorf("x: %w", errors.Join(errors.New("y"), io.EOF))),
+			target: io.EOF,
+			n:      3,
+		},
+		{
+			name:     "positions reported",
+			err:      errors.Join(io.EOF, deep, fmt.Errorf("x: %w", errors.Join(errors.New("y"), io.EOF))),
+			target:   io.EOF,
+			n:        2,
+			wantDiff: "2 occurrence(s) of EOF in tree; found 3 at $[0], $[1].Unwrap().Unwrap().Unwrap(), $[2].Unwrap()[1]",
+		},
+		{
+			name:   "same value twice",
+			err:    errors.Join(io.EOF, io.EOF),
+			target: io.EOF,
+			n:      2,
+		},
+		{
+			name:   "same wrapping error twice",
+			err:    errors.Join(deep, deep),
+			target: io.EOF,
+			n:      2,
+		},
+		{
+			name:   "Is() method counted at its own node",
+			err:    fmt.Errorf("w: %w", isEOF{}),
+			target: io.EOF,
+			n:      1,
+		},
+		{
+			name:   "cycle terminates",
+			err:    errors.Join(&cyclicError{}, io.EOF),
+			target: io.EOF,
+			n:      1,
+		},
+		{
+			name:   "cyclic target counted once",
+			err:    fmt.Errorf("w: %w", cyclic),
+			target: cyclic,
+			n:      1,
+		},
+		{
+			name:   "nil target never found",
+			err:    io.EOF,
+			target: nil,
+			n:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkDiff(t, tt.err, testerr.Count(tt.target, tt.n), tt.wantDiff)
+		})
+	}
+}
+
+func ExampleWalk() {
+	err := fmt.Errorf("sync: %w", errors.Join(
+		fmt.Errorf("shard 0: %w", io.EOF),
+		fmt.Errorf("shard 1: %w", io.ErrClosedPipe),
+	))
+
+	testerr.Walk(err, func(node error) bool {
+		fmt.Printf("%q\n", node.Error())
+		return node != io.EOF
+	})
+
+	// Output:
+	// "sync: shard 0: EOF\nshard 1: io: read/write on closed pipe"
+	// "shard 0: EOF\nshard 1: io: read/write on closed pipe"
+	// "shard 0: EOF"
+	// "EOF"
+}
+
+func ExampleAnywhereInTree() {
+	err := fmt.Errorf("sync: %w", errors.Join(
+		errors.New("shard 0: ok"),
+		fmt.Errorf("shard 1: %w", errors.Join(
+			errors.New("replica a: ok"),
+			errors.New("replica b: timeout after 30s"),
+		)),
+	))
+
+	printDiff(err, testerr.AnywhereInTree(testerr.MatchesRegexp(`^replica \w: timeout after \d+s$`)))
+	printDiff(err, testerr.AnywhereInTree(testerr.MessageIs("shard 2: ok")))
+
+	// Output:
+	// <empty>
+	// got error sync: shard 0: ok\nshard 1: replica a: ok\nreplica b: timeout after 30s; want error tree with any node matching (message "shard 2: ok"); none of 7 node(s) matched [leaves: "shard 0: ok", "replica a: ok", "replica b: timeout after 30s"]
+}
+
+func TestAnywhereInTree(t *testing.T) {
+	leaves := make([]error, 7)
+	for i := range leaves {
+		leaves[i] = fmt.Errorf("leaf %d", i)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		want     testerr.Want
+		wantDiff string // following "want"; empty if no diff is expected
+	}{
+		{
+			name:     "nil error",
+			want:     testerr.Contains(""),
+			wantDiff: `error tree with any node matching (containing substring "")`,
+		},
+		{
+			name: "root matches",
+			err:  io.EOF,
+			want: testerr.Equals(io.EOF),
+		},
+		{
+			name:     "nil Want never matches",
+			err:      io.EOF,
+			want:     nil,
+			wantDiff: `error tree with any node matching (nil); none of 1 node(s) matched [leaves: "EOF"]`,
+		},
+		{
+			name: "second branch of a join two levels down",
+			err: errors.Join(
+				errors.New("a"),
+				errors.Join(errors.New("b"), fmt.Errorf("c: %w", errors.New("needle"))),
+			),
+			want: testerr.MessageIs("needle"),
+		},
+		{
+			name:     "cycle terminates",
+			err:      errors.Join(&cyclicError{}, io.EOF),
+			want:     testerr.MessageIs("needle"),
+			wantDiff: `error tree with any node matching (message "needle"); none of 3 node(s) matched [leaves: "EOF"]`,
+		},
+		{
+			name:     "leaves truncated",
+			err:      errors.Join(leaves...),
+			want:     testerr.MessageIs("needle"),
+			wantDiff: `error tree with any node matching (message "needle"); none of 8 node(s) matched [leaves: "leaf 0", "leaf 1", "leaf 2", "leaf 3", "leaf 4", …and 2 more]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkDiff(t, tt.err, testerr.AnywhereInTree(tt.want), tt.wantDiff)
+		})
+	}
+}
+
+func TestWalkStops(t *testing.T) {
+	err := errors.Join(fmt.Errorf("a: %w", io.EOF), errors.New("b"))
+
+	var visited []string
+	testerr.Walk(err, func(node error) bool {
+		visited = append(visited, node.Error())
+		return node != io.EOF
+	})
+	if want := []string{err.Error(), "a: EOF", "EOF"}; fmt.Sprint(visited) != fmt.Sprint(want) {
+		t.Errorf("Walk() stopping at EOF visited %q; want %q", visited, want)
+	}
+}
+
+// fieldError is comparable by type but, as `v` can hold a non-comparable
+// value, comparing two of them can panic.
+type fieldError struct {
+	v    any
+	next error
+}
+
+func (e fieldError) Error() string { return fmt.Sprintf("field %v", e.v) }
+func (e fieldError) Unwrap() error { return e.next }
+
+// derefError dereferences its receiver to unwrap, which is nil in the test.
+type derefError struct{ next error }
+
+func (e *derefError) Error() string { return "deref" }
+func (e *derefError) Unwrap() error { return e.next }
+
+func TestWalkUnusualNodes(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []string // %T of each visited node
+	}{
+		{
+			name: "non-comparable values in comparable type",
+			err:  fieldError{v: []int{0}, next: fieldError{v: []int{1}, next: io.EOF}},
+			want: []string{"testerr_test.fieldError", "testerr_test.fieldError", "*errors.errorString"},
+		},
+		{
+			name: "typed nil",
+			err:  errors.Join(io.EOF, (*derefError)(nil)),
+			want: []string{"*errors.joinError", "*errors.errorString"},
+		},
+		{
+			name: "typed nil root",
+			err:  (*derefError)(nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var visited []string
+			testerr.Walk(tt.err, func(node error) bool {
+				visited = append(visited, fmt.Sprintf("%T", node))
+				return true
+			})
+			if fmt.Sprint(visited) != fmt.Sprint(tt.want) {
+				t.Errorf("Walk() visited %q; want %q", visited, tt.want)
+			}
+			// Count() walks the tree again, recording paths, on mismatch.
+			if diff := testerr.Diff(tt.err, testerr.Count(io.EOF, 2)); diff == "" {
+				t.Errorf("Diff(…, Count(io.EOF, 2)) got empty diff")
+			}
+		})
+	}
+}